@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/network"
+)
+
+type Config struct {
+	Window     config.Window `json:"window"`
+	MoveSpeed  float64       `json:"move_speed" help:"force applied by the arrow keys" min:"0"`
+	Seed       int64         `json:"seed" help:"random seed, picked from the clock when 0"`
+	Rivals     string        `json:"rivals" help:"comma separated rival strategies: greedy, cautious, intercept"`
+	Difficulty string        `json:"difficulty" help:"rival difficulty: easy, normal or hard"`
+	Network    Network       `json:"network"`
+}
+
+type Network struct {
+	Mode    string  `json:"mode" help:"local, server, client, or loopback to run a server and client in one process"`
+	Address string  `json:"address" help:"address the server listens on or the client connects to"`
+	Latency float64 `json:"latency" help:"simulated one way latency in seconds" min:"0"`
+	Jitter  float64 `json:"jitter" help:"simulated latency variation in seconds" min:"0"`
+	Loss    float64 `json:"loss" help:"simulated chance of losing each packet" min:"0" max:"1"`
+}
+
+func (n Network) link() network.Link {
+	return network.Link{
+		Latency: secondsToDuration(n.Latency),
+		Jitter:  secondsToDuration(n.Jitter),
+		Loss:    n.Loss,
+	}
+}
+
+func defaultConfig() Config {
+	return Config{
+		Window: config.Window{
+			Width:        1024,
+			Height:       768,
+			CanvasWidth:  1024 / 2,
+			CanvasHeight: 768 / 2,
+			MaxFps:       60,
+		},
+		MoveSpeed:  7500,
+		Rivals:     "greedy,cautious,intercept",
+		Difficulty: "normal",
+		Network: Network{
+			Mode:    "local",
+			Address: "localhost:7777",
+		},
+	}
+}
+
+func (c Config) Validate() error {
+	if _, ok := difficulties[c.Difficulty]; !ok {
+		return fmt.Errorf("unknown difficulty %q", c.Difficulty)
+	}
+	switch c.Network.Mode {
+	case "local", "server", "client", "loopback":
+	default:
+		return fmt.Errorf("unknown network mode %q", c.Network.Mode)
+	}
+	_, err := parseStrategies(c.Rivals)
+	return err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/events"
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+// AppleEaten is published when the player or a rival reaches an apple.
+type AppleEaten struct {
+	Toon  *objects.Toon
+	Apple *objects.Apple
+	// Size is the size of the toon when it ate the apple.
+	Size float64
+}
+
+// subscribeScoring adds points to the score of the toon that ate an apple.
+// scoreOf returns nil for toons that don't keep score.
+func subscribeScoring(bus *events.Bus, scoreOf func(toon *objects.Toon) *int) *events.Subscription {
+	return events.Subscribe(bus, func(e AppleEaten) {
+		if score := scoreOf(e.Toon); score != nil {
+			*score += int(3 * e.Size)
+		}
+	})
+}
+
+// subscribeGrowth grows or shrinks the toon depending on the apple.
+func subscribeGrowth(bus *events.Bus) *events.Subscription {
+	return events.Subscribe(bus, func(e AppleEaten) {
+		e.Apple.Eat(e.Toon)
+	})
+}
+
+// subscribeRespawn moves eaten apples somewhere away from the toon.
+func subscribeRespawn(bus *events.Bus, rng *rng.RNG, bounds pixel.Rect) *events.Subscription {
+	spawning := rng.Stream("spawning")
+	return events.Subscribe(bus, func(e AppleEaten) {
+		e.Apple.Respawn(spawning, e.Toon, bounds)
+	})
+}
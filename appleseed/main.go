@@ -4,141 +4,170 @@ import (
 	"fmt"
 	"github.com/explodes/gogames"
 	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/events"
+	"github.com/explodes/gogames/highscores"
+	"github.com/explodes/gogames/persistence"
+	"github.com/explodes/gogames/rng"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
 	"golang.org/x/image/colornames"
-	"math/rand"
+	"golang.org/x/image/font/basicfont"
 	"os"
 	"time"
 )
 
 const (
-	title                     = "Appleseed"
-	width, height             = 1024, 768
-	canvasWidth, canvasHeight = width / 2, height / 2
-	maxFps                    = 60
+	title     = "Appleseed"
+	scoreMode = "sandbox"
 )
 
-func run() {
-	rand.Seed(time.Now().UnixNano())
+func run(cfg Config) {
+	rng := newRNG(cfg)
 
-	cfg := pixelgl.WindowConfig{
+	winCfg := pixelgl.WindowConfig{
 		Title:  title,
-		Bounds: pixel.R(0, 0, width, height),
+		Bounds: pixel.R(0, 0, float64(cfg.Window.Width), float64(cfg.Window.Height)),
 		VSync:  true,
 	}
-	win, err := pixelgl.NewWindow(cfg)
+	win, err := pixelgl.NewWindow(winCfg)
 	if err != nil {
 		exitWith(err, "unable to create window")
 	}
 	win.SetSmooth(true)
 
-	canvas := pixelgl.NewCanvas(pixel.R(0, 0, canvasWidth, canvasHeight))
+	canvas := pixelgl.NewCanvas(fieldBounds(cfg))
 
 	imd := imdraw.New(nil)
 	imd.Precision = 32
 
-	fpsLimit := games.NewFpsLimiter(maxFps)
+	fpsLimit := games.NewFpsLimiter(cfg.Window.MaxFps)
 
-	toon := &objects.Toon{
-		Size:    3,
-		Physics: games.NewPhysicsWithPosition(10, 10),
+	store, err := newSaveStore()
+	if err != nil {
+		fmt.Printf("unable to open saved games: %v\n", err)
 	}
 
-	var apples []*objects.Apple
+	level := difficulties[cfg.Difficulty]
+	rivalStrategies, err := parseStrategies(cfg.Rivals)
+	if err != nil {
+		exitWith(err, "invalid rivals")
+	}
 
-	for i := 0; i < 100; i++ {
-		apples = append(apples, &objects.Apple{
-			Physics: games.NewPhysicsWithPosition(rand.Float64()*canvasWidth, rand.Float64()*canvasHeight),
-			Grower:  i%4 != 0,
-		})
+	toon, apples, score := newGame(rng, canvas.Bounds())
+	rivals := newRivals(rng, canvas.Bounds(), rivalStrategies, level)
+	if store != nil {
+		if savedToon, savedApples, savedScore, err := loadGame(store); err == nil {
+			toon, apples, score = savedToon, savedApples, savedScore
+		} else if err != persistence.ErrNoSave {
+			fmt.Printf("unable to load saved game: %v\n", err)
+		}
+	}
+
+	scores, err := highscores.Open("appleseed")
+	if err != nil {
+		fmt.Printf("unable to open high scores: %v\n", err)
 	}
 
+	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
+	scoresTxt := text.New(pixel.ZV, atlas)
+	showScores := func(rank int) {
+		scoresTxt.Clear()
+		scoresTxt.Dot = scoresTxt.Orig
+		writeResults(scoresTxt, score, rivals)
+		if scores != nil {
+			fmt.Fprint(scoresTxt, "\nHigh scores\n\n")
+			highscores.WriteEntries(scoresTxt, scores.Entries(scoreMode), rank)
+		}
+		fmt.Fprint(scoresTxt, "\nENTER to play again")
+	}
+
+	bus := events.New()
+	subscribeScoring(bus, func(t *objects.Toon) *int {
+		if t == toon {
+			return &score
+		}
+		for _, r := range rivals {
+			if r.Toon == t {
+				return &r.Score
+			}
+		}
+		return nil
+	})
+	subscribeGrowth(bus)
+	subscribeRespawn(bus, rng, canvas.Bounds())
+
+	var prompt *highscores.NamePrompt
+	roundOver := false
+	elapsed := 0.0
+
 	last := time.Now()
-	score := 0
 
 	for !win.Closed() {
 		fpsLimit.StartFrame()
 		dt := time.Since(last).Seconds()
 		last = time.Now()
 
-		const movespeed = 7500
-
-		var dx, dy float64
-
-		if win.Pressed(pixelgl.KeyUp) {
-			if toon.Velocity.Y < 0 {
-				dy += 200 * dt * movespeed
-			}
-			dy += movespeed
-		}
-		if win.Pressed(pixelgl.KeyDown) {
-			if toon.Velocity.Y > 0 {
-				dy -= 200 * dt * movespeed
-			}
-			dy -= movespeed
-		}
-		if win.Pressed(pixelgl.KeyLeft) {
-			if toon.Velocity.X > 0 {
-				dx -= 200 * dt * movespeed
+		if roundOver {
+			if prompt != nil {
+				prompt.HandleInput(win)
+				prompt.Update(dt)
+				if prompt.Done() {
+					rank := 0
+					if !prompt.Cancelled() {
+						rank, err = scores.Add(scoreMode, highscores.HighestFirst, highscores.Entry{
+							Name:  prompt.Name(),
+							Score: score,
+							Time:  secondsToDuration(elapsed),
+						})
+						if err != nil {
+							fmt.Printf("unable to save high score: %v\n", err)
+						}
+					}
+					prompt = nil
+					showScores(rank)
+				}
+			} else if win.JustPressed(pixelgl.KeyEnter) {
+				toon, apples, score = newGame(rng, canvas.Bounds())
+				rivals = newRivals(rng, canvas.Bounds(), rivalStrategies, level)
+				bus.Clear()
+				elapsed = 0
+				roundOver = false
 			}
-			dx -= movespeed
-		}
-		if win.Pressed(pixelgl.KeyRight) {
-			if toon.Velocity.X < 0 {
-				dx += 200 * dt * movespeed
+			// freeze the field while the round is over
+			dt = 0
+		} else if win.JustPressed(pixelgl.KeyEnter) {
+			roundOver = true
+			if scores != nil && scores.Qualifies(scoreMode, highscores.HighestFirst, score, secondsToDuration(elapsed)) {
+				prompt = highscores.NewNamePrompt(fmt.Sprintf("Scored %d! Enter your name:", score), scores.LastName(), 12, atlas)
+			} else {
+				showScores(0)
 			}
-			dx += movespeed
 		}
-		toon.Move(dt*dx, dt*dy)
+		elapsed += dt
+
+		toon.Steer(readControls(win), cfg.MoveSpeed, dt)
 
 		toon.Update(dt)
 
 		cb := canvas.Bounds()
-		if toon.Position.X < cb.Min.X {
-			toon.Position.X = cb.Min.X
-			toon.Velocity.X = 0
-			toon.Acceleration.X = 0
-		} else if toon.Position.X > cb.Max.X {
-			toon.Position.X = cb.Max.X
-			toon.Velocity.X = 0
-			toon.Acceleration.X = 0
-		}
-		if toon.Position.Y < cb.Min.Y {
-			toon.Position.Y = cb.Min.Y
-			toon.Velocity.Y = 0
-			toon.Acceleration.Y = 0
-		} else if toon.Position.Y > cb.Max.Y {
-			toon.Position.Y = cb.Max.Y
-			toon.Velocity.Y = 0
-			toon.Acceleration.Y = 0
+		toon.KeepWithin(cb)
+
+		f := field{player: toon, apples: apples}
+		for _, r := range rivals {
+			r.Think(dt, f)
+			r.Steer(f)
+			r.Update(dt)
+			r.Toon.KeepWithin(cb)
 		}
 
 		for _, apple := range apples {
-			distance := games.Distance(apple.Position, toon.Position)
-			if distance <= toon.Size {
-				score += int(3 * toon.Size)
-				if apple.Grower {
-					toon.Grow()
-				} else {
-					toon.Shrink()
-				}
-				for i := 0; i < 10; i++ {
-					newPos := pixel.V(rand.Float64()*canvasWidth, rand.Float64()*canvasHeight)
-					if games.Distance(toon.Position, newPos) > toon.Size {
-						apple.Position = newPos
-						break
-					}
-				}
-				apple.Velocity = pixel.ZV
-				apple.Acceleration = pixel.ZV
-			} else if distance <= 4*toon.Size {
-				gx := 5 * dt * (toon.Position.X - apple.Position.X)
-				gy := 5 * dt * (toon.Position.Y - apple.Position.Y)
-				apple.Force(games.SignedSqrt(gx), games.SignedSqrt(gy))
-				apple.Update(dt)
-				apple.Position = games.LimitWithinRect(apple.Position, canvas.Bounds())
+			if eater := eatenBy(apple, toon, rivals); eater != nil {
+				events.Publish(bus, AppleEaten{Toon: eater, Apple: apple, Size: eater.Size})
+			} else {
+				apple.Attract(toon, cb, dt)
 			}
 		}
 
@@ -146,20 +175,98 @@ func run() {
 		for _, apple := range apples {
 			apple.Draw(imd)
 		}
+		for _, r := range rivals {
+			r.Toon.Draw(imd)
+		}
 		toon.Draw(imd)
 
 		canvas.Clear(colornames.Black)
 		imd.Draw(canvas)
 
+		if prompt != nil {
+			prompt.Draw(canvas, canvas.Bounds().Center())
+		} else if roundOver {
+			scoresTxt.Draw(canvas, pixel.IM.Moved(canvas.Bounds().Center().Sub(scoresTxt.Bounds().Center())))
+		}
+
 		games.DrawCanvasInWindow(colornames.White, win, canvas)
 
+		bus.Dispatch()
+
 		fpsLimit.WaitForNextFrame()
-		win.SetTitle(fmt.Sprintf("%s | score: %d | fps %.0f", title, score, fpsLimit.CurrentFrameFps()))
+		win.SetTitle(fmt.Sprintf("%s | seed %d | score: %d%s | fps %.0f", title, rng.Seed(), score, rivalScores(rivals), fpsLimit.CurrentFrameFps()))
+	}
+
+	if store != nil {
+		if err := saveGame(store, toon, apples, score); err != nil {
+			fmt.Printf("unable to save game: %v\n", err)
+		}
+	}
+}
+
+func readControls(win *pixelgl.Window) objects.Controls {
+	return objects.Controls{
+		Up:    win.Pressed(pixelgl.KeyUp),
+		Down:  win.Pressed(pixelgl.KeyDown),
+		Left:  win.Pressed(pixelgl.KeyLeft),
+		Right: win.Pressed(pixelgl.KeyRight),
 	}
 }
 
+// eatenBy returns the toon that reached the apple, the player first, or nil.
+func eatenBy(apple *objects.Apple, player *objects.Toon, rivals []*Rival) *objects.Toon {
+	if games.Near(apple.Position, player.Position, player.Size) {
+		return player
+	}
+	for _, r := range rivals {
+		if games.Near(apple.Position, r.Toon.Position, r.Toon.Size) {
+			return r.Toon
+		}
+	}
+	return nil
+}
+
+func newGame(rng *rng.RNG, bounds pixel.Rect) (*objects.Toon, []*objects.Apple, int) {
+	spawning := rng.Stream("spawning")
+
+	toon := &objects.Toon{
+		Size:    3,
+		Physics: games.NewPhysicsWithPosition(10, 10),
+	}
+
+	var apples []*objects.Apple
+
+	for i := 0; i < 100; i++ {
+		apples = append(apples, &objects.Apple{
+			Physics: games.NewPhysicsWithPosition(games.PointInRect(spawning, bounds).XY()),
+			Grower:  i%4 != 0,
+		})
+	}
+
+	return toon, apples, 0
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
 func main() {
-	pixelgl.Run(run)
+	cfg := defaultConfig()
+	if err := config.Load("appleseed", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "invalid config")
+	}
+	switch cfg.Network.Mode {
+	case "server":
+		runServer(cfg)
+	case "client", "loopback":
+		pixelgl.Run(func() {
+			runClient(cfg)
+		})
+	default:
+		pixelgl.Run(func() {
+			run(cfg)
+		})
+	}
 }
 
 func exitWith(err error, msg string, args ...interface{}) {
@@ -0,0 +1,200 @@
+package multiplayer
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/explodes/gogames/appleseed/objects"
+)
+
+const (
+	// DefaultInterpolationDelay is how far behind the newest snapshot the
+	// client renders, long enough to ride out a couple of lost packets.
+	DefaultInterpolationDelay = 3 * tickSeconds
+	// helloInterval is how often hello is resent until the server answers.
+	helloInterval = 0.5
+)
+
+// Client joins a server, sends inputs and interpolates the snapshots it
+// receives.
+type Client struct {
+	conn   net.PacketConn
+	server net.Addr
+	start  time.Time
+	// InterpolationDelay is in seconds.
+	InterpolationDelay float64
+
+	mu        sync.Mutex
+	id        uint16
+	joined    bool
+	sequence  uint32
+	lastHello float64
+	received  map[uint32]*Snapshot
+	buffer    []*Snapshot
+	latest    uint32
+	offset    float64
+	hasOffset bool
+	err       error
+}
+
+// Dial starts talking to the server at addr over conn.
+func Dial(conn net.PacketConn, server net.Addr) *Client {
+	c := &Client{
+		conn:               conn,
+		server:             server,
+		start:              time.Now(),
+		InterpolationDelay: DefaultInterpolationDelay,
+		received:           make(map[uint32]*Snapshot),
+		lastHello:          -helloInterval,
+	}
+	go c.read()
+	return c
+}
+
+func (c *Client) now() float64 {
+	return time.Since(c.start).Seconds()
+}
+
+func (c *Client) read() {
+	buf := make([]byte, maxPacket)
+	for {
+		n, _, err := c.conn.ReadFrom(buf)
+		if err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		c.handle(buf[:n])
+	}
+}
+
+func (c *Client) handle(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := &reader{data: data[1:]}
+	switch data[0] {
+	case msgWelcome:
+		id := r.u16()
+		if r.err == nil {
+			c.id = id
+			c.joined = true
+		}
+	case msgSnapshot:
+		snap, err := decodeSnapshot(r, func(tick uint32) *Snapshot {
+			return c.received[tick]
+		})
+		if err != nil {
+			// the next snapshot will be based on an older ack or sent whole
+			return
+		}
+		c.receive(snap)
+	}
+}
+
+func (c *Client) receive(snap *Snapshot) {
+	if _, ok := c.received[snap.Tick]; ok {
+		return
+	}
+	c.received[snap.Tick] = snap
+	if snap.Tick > c.latest {
+		c.latest = snap.Tick
+	}
+	for tick := range c.received {
+		if c.latest-tick >= historySize {
+			delete(c.received, tick)
+		}
+	}
+
+	// the smallest gap between server and local time is the one with the
+	// least network delay
+	offset := snap.Time() - c.now()
+	if !c.hasOffset || offset > c.offset {
+		c.offset = offset
+		c.hasOffset = true
+	}
+
+	i := sort.Search(len(c.buffer), func(i int) bool { return c.buffer[i].Tick > snap.Tick })
+	c.buffer = append(c.buffer, nil)
+	copy(c.buffer[i+1:], c.buffer[i:])
+	c.buffer[i] = snap
+}
+
+// ID returns the player's ID once the server has answered.
+func (c *Client) ID() (uint16, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.id, c.joined
+}
+
+// Err returns the error that stopped the client reading, if any.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Send sends the controls held this frame, or hello until joined.
+func (c *Client) Send(controls objects.Controls) error {
+	c.mu.Lock()
+	joined := c.joined
+	c.sequence++
+	in := input{Sequence: c.sequence, Ack: c.latest, Controls: controls}
+	now := c.now()
+	sendHello := !joined && now-c.lastHello >= helloInterval
+	if sendHello {
+		c.lastHello = now
+	}
+	c.mu.Unlock()
+
+	switch {
+	case sendHello:
+		_, err := c.conn.WriteTo(encodeHello(), c.server)
+		return err
+	case joined:
+		_, err := c.conn.WriteTo(encodeInput(in), c.server)
+		return err
+	}
+	return nil
+}
+
+// State returns the world as it was InterpolationDelay seconds before the
+// newest snapshot, or nil before the first snapshot arrives.
+func (c *Client) State() *Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.buffer) == 0 {
+		return nil
+	}
+	renderTime := c.now() + c.offset - c.InterpolationDelay
+
+	// drop snapshots that are older than the pair around renderTime
+	for len(c.buffer) > 2 && c.buffer[1].Time() <= renderTime {
+		c.buffer = c.buffer[1:]
+	}
+	if len(c.buffer) == 1 || renderTime <= c.buffer[0].Time() {
+		return c.buffer[0]
+	}
+	a, b := c.buffer[0], c.buffer[1]
+	if renderTime >= b.Time() {
+		// starved of snapshots, hold the newest rather than guess
+		return b
+	}
+	return Interpolate(a, b, (renderTime-a.Time())/(b.Time()-a.Time()))
+}
+
+// Close says goodbye to the server and closes the connection.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	joined := c.joined
+	c.mu.Unlock()
+	if joined {
+		c.conn.WriteTo(encodeBye(), c.server)
+	}
+	return c.conn.Close()
+}
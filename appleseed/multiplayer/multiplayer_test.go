@@ -0,0 +1,181 @@
+package multiplayer
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/network"
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+func decode(t *testing.T, packet []byte, base *Snapshot) *Snapshot {
+	t.Helper()
+	if packet[0] != msgSnapshot {
+		t.Fatalf("packet type %d, want snapshot", packet[0])
+	}
+	snap, err := decodeSnapshot(&reader{data: packet[1:]}, func(tick uint32) *Snapshot {
+		if base != nil && base.Tick == tick {
+			return base
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+func TestSnapshotDelta(t *testing.T) {
+	base := &Snapshot{
+		Tick: 10,
+		Toons: []ToonState{
+			{ID: 1, Position: pixel.V(10, 20), Size: 3, Score: 0},
+			{ID: 2, Position: pixel.V(30, 40), Size: 4, Score: 5},
+			{ID: 3, Position: pixel.V(50, 60), Size: 5, Score: 9},
+		},
+		Apples: []AppleState{
+			{Position: pixel.V(1, 2), Grower: true},
+			{Position: pixel.V(3, 4), Grower: true},
+			{Position: pixel.V(5, 6), Grower: false},
+		},
+	}
+
+	tests := []struct {
+		name string
+		snap *Snapshot
+	}{
+		{"unchanged", &Snapshot{Tick: 11, Toons: base.Toons, Apples: base.Apples}},
+		{"changed", &Snapshot{
+			Tick: 12,
+			Toons: []ToonState{
+				{ID: 1, Position: pixel.V(10.5, 20), Size: 3, Score: 0},
+				{ID: 2, Position: pixel.V(30, 40), Size: 4.25, Score: 12},
+				{ID: 3, Position: pixel.V(50, 60), Size: 5, Score: 9},
+			},
+			Apples: []AppleState{
+				{Position: pixel.V(1, 2), Grower: true},
+				{Position: pixel.V(300, 200), Grower: true},
+				{Position: pixel.V(5, 6), Grower: true},
+			},
+		}},
+		{"added and removed", &Snapshot{
+			Tick: 13,
+			Toons: []ToonState{
+				{ID: 1, Position: pixel.V(10, 20), Size: 3, Score: 0},
+				{ID: 3, Position: pixel.V(50, 60), Size: 5, Score: 9},
+				{ID: 4, Position: pixel.V(70, 80), Size: 3, Score: 0},
+			},
+			Apples: []AppleState{
+				{Position: pixel.V(1, 2), Grower: true},
+				{Position: pixel.V(3, 4), Grower: true},
+				{Position: pixel.V(5, 6), Grower: false},
+				{Position: pixel.V(7, 8), Grower: true},
+			},
+		}},
+		{"fewer apples", &Snapshot{
+			Tick:   14,
+			Toons:  base.Toons,
+			Apples: base.Apples[:1],
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delta := encodeSnapshot(base, test.snap)
+			if got := decode(t, delta, base); !reflect.DeepEqual(got, test.snap) {
+				t.Errorf("delta decoded to %+v, want %+v", got, test.snap)
+			}
+			full := encodeSnapshot(nil, test.snap)
+			if got := decode(t, full, nil); !reflect.DeepEqual(got, test.snap) {
+				t.Errorf("full snapshot decoded to %+v, want %+v", got, test.snap)
+			}
+			if len(delta) > len(full) {
+				t.Errorf("delta is %d bytes, larger than the full %d", len(delta), len(full))
+			}
+		})
+	}
+
+	_, err := decodeSnapshot(&reader{data: encodeSnapshot(base, tests[1].snap)[1:]}, func(uint32) *Snapshot { return nil })
+	if err == nil {
+		t.Error("decoded a delta without its base")
+	}
+}
+
+func listenLossy(t *testing.T, link network.Link, rng *rng.RNG) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return network.NewLossyConn(conn, link, rng)
+}
+
+func TestLoopback(t *testing.T) {
+	link := network.Link{
+		Latency: 30 * time.Millisecond,
+		Jitter:  10 * time.Millisecond,
+		Loss:    0.1,
+	}
+	r := rng.New(1)
+
+	serverConn := listenLossy(t, link, r.Stream("server link"))
+	defer serverConn.Close()
+	server := NewServer(serverConn, NewWorld(r.Stream("server"), pixel.R(0, 0, 400, 300), 7500))
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run(stop)
+	}()
+
+	client := Dial(listenLossy(t, link, r.Stream("client link")), serverConn.LocalAddr())
+	defer client.Close()
+
+	ticker := time.NewTicker(time.Second / TickRate)
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); <-ticker.C {
+		if err := client.Send(objects.Controls{Right: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ticker.Stop()
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	id, joined := client.ID()
+	if !joined {
+		t.Fatal("client never joined")
+	}
+
+	// with the server stopped, the client runs out of snapshots and holds the
+	// newest one it received
+	time.Sleep(link.Latency + link.Jitter + 3*time.Duration(client.InterpolationDelay*float64(time.Second)))
+	state := client.State()
+	if state == nil {
+		t.Fatal("client received no snapshots")
+	}
+	client.mu.Lock()
+	latest := client.latest
+	client.mu.Unlock()
+	if state.Tick != latest {
+		t.Fatalf("client settled on tick %d, newest received is %d", state.Tick, latest)
+	}
+
+	last := server.history[server.world.tick%historySize]
+	if behind := last.Tick - state.Tick; behind > 10 {
+		t.Errorf("client settled %d ticks behind the server", behind)
+	}
+	want := server.history[state.Tick%historySize]
+	if want == nil || want.Tick != state.Tick {
+		t.Fatalf("server no longer has tick %d", state.Tick)
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("client state at tick %d differs from the server's", state.Tick)
+	}
+	if _, ok := state.Toon(id); !ok {
+		t.Errorf("player %d is missing from the client state", id)
+	}
+}
@@ -0,0 +1,321 @@
+package multiplayer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/faiface/pixel"
+)
+
+// protocolVersion is sent with hello so mismatched builds refuse to talk.
+const protocolVersion = 1
+
+// maxPacket is the largest datagram read.
+const maxPacket = 2048
+
+const (
+	msgHello byte = iota + 1
+	msgWelcome
+	msgInput
+	msgSnapshot
+	msgBye
+)
+
+const (
+	toonPosition byte = 1 << iota
+	toonSize
+	toonScore
+)
+
+const (
+	applePosition byte = 1 << iota
+	appleGrower
+)
+
+const (
+	controlUp byte = 1 << iota
+	controlDown
+	controlLeft
+	controlRight
+)
+
+var errShortPacket = errors.New("short packet")
+
+// writer appends little-endian values to a packet.
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) u8(v byte) {
+	w.WriteByte(v)
+}
+
+func (w *writer) u16(v uint16) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+
+func (w *writer) u32(v uint32) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+
+func (w *writer) f32(v float64) {
+	w.u32(math.Float32bits(float32(v)))
+}
+
+func (w *writer) vec(v pixel.Vec) {
+	w.f32(v.X)
+	w.f32(v.Y)
+}
+
+// reader reads little-endian values from a packet, remembering the first
+// error.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.data) < n {
+		r.err = errShortPacket
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) u8() byte {
+	return r.take(1)[0]
+}
+
+func (r *reader) u16() uint16 {
+	return binary.LittleEndian.Uint16(r.take(2))
+}
+
+func (r *reader) u32() uint32 {
+	return binary.LittleEndian.Uint32(r.take(4))
+}
+
+func (r *reader) f32() float64 {
+	return float64(math.Float32frombits(r.u32()))
+}
+
+func (r *reader) vec() pixel.Vec {
+	x := r.f32()
+	return pixel.V(x, r.f32())
+}
+
+func encodeHello() []byte {
+	return []byte{msgHello, protocolVersion}
+}
+
+func encodeWelcome(id uint16) []byte {
+	var w writer
+	w.u8(msgWelcome)
+	w.u16(id)
+	return w.Bytes()
+}
+
+func encodeBye() []byte {
+	return []byte{msgBye}
+}
+
+// input is what clients send every frame. Ack is the newest snapshot the
+// client has, which the server uses as the base for deltas.
+type input struct {
+	Sequence uint32
+	Ack      uint32
+	Controls objects.Controls
+}
+
+func encodeInput(in input) []byte {
+	var w writer
+	w.u8(msgInput)
+	w.u32(in.Sequence)
+	w.u32(in.Ack)
+	var bits byte
+	for _, c := range []struct {
+		held bool
+		bit  byte
+	}{
+		{in.Controls.Up, controlUp},
+		{in.Controls.Down, controlDown},
+		{in.Controls.Left, controlLeft},
+		{in.Controls.Right, controlRight},
+	} {
+		if c.held {
+			bits |= c.bit
+		}
+	}
+	w.u8(bits)
+	return w.Bytes()
+}
+
+func decodeInput(r *reader) (input, error) {
+	in := input{
+		Sequence: r.u32(),
+		Ack:      r.u32(),
+	}
+	bits := r.u8()
+	in.Controls = objects.Controls{
+		Up:    bits&controlUp != 0,
+		Down:  bits&controlDown != 0,
+		Left:  bits&controlLeft != 0,
+		Right: bits&controlRight != 0,
+	}
+	return in, r.err
+}
+
+// encodeSnapshot writes snap as changes from base, which may be nil to send
+// everything. Only toons and apples that differ from base are written.
+func encodeSnapshot(base, snap *Snapshot) []byte {
+	if base == nil {
+		base = &Snapshot{}
+	}
+	var w writer
+	w.u8(msgSnapshot)
+	w.u32(snap.Tick)
+	w.u32(base.Tick)
+
+	var changed []ToonState
+	var masks []byte
+	for _, t := range snap.Toons {
+		old, ok := base.Toon(t.ID)
+		var mask byte
+		if !ok || old.Position != t.Position {
+			mask |= toonPosition
+		}
+		if !ok || old.Size != t.Size {
+			mask |= toonSize
+		}
+		if !ok || old.Score != t.Score {
+			mask |= toonScore
+		}
+		if mask != 0 {
+			changed = append(changed, t)
+			masks = append(masks, mask)
+		}
+	}
+	w.u16(uint16(len(changed)))
+	for i, t := range changed {
+		w.u16(t.ID)
+		w.u8(masks[i])
+		if masks[i]&toonPosition != 0 {
+			w.vec(t.Position)
+		}
+		if masks[i]&toonSize != 0 {
+			w.f32(t.Size)
+		}
+		if masks[i]&toonScore != 0 {
+			w.u32(uint32(t.Score))
+		}
+	}
+
+	var removed []uint16
+	for _, t := range base.Toons {
+		if _, ok := snap.Toon(t.ID); !ok {
+			removed = append(removed, t.ID)
+		}
+	}
+	w.u16(uint16(len(removed)))
+	for _, id := range removed {
+		w.u16(id)
+	}
+
+	w.u16(uint16(len(snap.Apples)))
+	var appleChanges writer
+	count := 0
+	for i, a := range snap.Apples {
+		var mask byte
+		if i >= len(base.Apples) || base.Apples[i].Position != a.Position {
+			mask |= applePosition
+		}
+		if i >= len(base.Apples) || base.Apples[i].Grower != a.Grower {
+			mask |= appleGrower
+		}
+		if mask == 0 {
+			continue
+		}
+		count++
+		appleChanges.u16(uint16(i))
+		appleChanges.u8(mask)
+		if mask&applePosition != 0 {
+			appleChanges.vec(a.Position)
+		}
+		if mask&appleGrower != 0 {
+			var grower byte
+			if a.Grower {
+				grower = 1
+			}
+			appleChanges.u8(grower)
+		}
+	}
+	w.u16(uint16(count))
+	w.Write(appleChanges.Bytes())
+	return w.Bytes()
+}
+
+// decodeSnapshot rebuilds a snapshot from a delta. base looks up earlier
+// snapshots by tick and returns nil for ones the client no longer has.
+func decodeSnapshot(r *reader, base func(tick uint32) *Snapshot) (*Snapshot, error) {
+	snap := &Snapshot{Tick: r.u32()}
+	baseTick := r.u32()
+	from := &Snapshot{}
+	if baseTick != 0 {
+		if from = base(baseTick); from == nil {
+			return nil, fmt.Errorf("missing base snapshot %d", baseTick)
+		}
+	}
+
+	toons := make(map[uint16]ToonState, len(from.Toons))
+	for _, t := range from.Toons {
+		toons[t.ID] = t
+	}
+	for n := int(r.u16()); n > 0 && r.err == nil; n-- {
+		id := r.u16()
+		mask := r.u8()
+		t := toons[id]
+		t.ID = id
+		if mask&toonPosition != 0 {
+			t.Position = r.vec()
+		}
+		if mask&toonSize != 0 {
+			t.Size = r.f32()
+		}
+		if mask&toonScore != 0 {
+			t.Score = int(int32(r.u32()))
+		}
+		toons[id] = t
+	}
+	for n := int(r.u16()); n > 0 && r.err == nil; n-- {
+		delete(toons, r.u16())
+	}
+	for _, t := range toons {
+		snap.Toons = append(snap.Toons, t)
+	}
+	sortToons(snap.Toons)
+
+	snap.Apples = make([]AppleState, r.u16())
+	copy(snap.Apples, from.Apples)
+	for n := int(r.u16()); n > 0 && r.err == nil; n-- {
+		i := int(r.u16())
+		mask := r.u8()
+		if i >= len(snap.Apples) {
+			return nil, fmt.Errorf("apple %d out of range", i)
+		}
+		if mask&applePosition != 0 {
+			snap.Apples[i].Position = r.vec()
+		}
+		if mask&appleGrower != 0 {
+			snap.Apples[i].Grower = r.u8() != 0
+		}
+	}
+	return snap, r.err
+}
@@ -0,0 +1,171 @@
+package multiplayer
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// historySize is how many ticks of snapshots are kept as delta bases.
+	historySize = 64
+	// clientTimeout drops clients that have gone quiet.
+	clientTimeout = 5 * time.Second
+	maxPlayers    = 16
+)
+
+type packet struct {
+	data []byte
+	addr net.Addr
+}
+
+type remoteClient struct {
+	id       uint16
+	addr     net.Addr
+	lastSeen time.Time
+	sequence uint32
+	acked    uint32
+}
+
+// Server runs the authoritative simulation and streams snapshots to clients.
+type Server struct {
+	conn    net.PacketConn
+	world   *World
+	clients map[string]*remoteClient
+	history [historySize]*Snapshot
+	nextID  uint16
+	// Log receives joins, leaves and protocol errors, when set.
+	Log func(format string, args ...interface{})
+}
+
+func NewServer(conn net.PacketConn, world *World) *Server {
+	return &Server{
+		conn:    conn,
+		world:   world,
+		clients: make(map[string]*remoteClient),
+		nextID:  1,
+	}
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.Log != nil {
+		s.Log(format, args...)
+	}
+}
+
+// Run simulates at TickRate until stop is closed or the connection fails.
+func (s *Server) Run(stop <-chan struct{}) error {
+	packets := make(chan packet, 256)
+	readErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, maxPacket)
+		for {
+			n, addr, err := s.conn.ReadFrom(buf)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			packets <- packet{data: append([]byte(nil), buf[:n]...), addr: addr}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second / TickRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-readErr:
+			return fmt.Errorf("unable to read packet: %v", err)
+		case p := <-packets:
+			s.handle(p, time.Now())
+		case now := <-ticker.C:
+			s.tick(now)
+		}
+	}
+}
+
+func (s *Server) handle(p packet, now time.Time) {
+	if len(p.data) == 0 {
+		return
+	}
+	key := p.addr.String()
+	c := s.clients[key]
+	r := &reader{data: p.data[1:]}
+	switch p.data[0] {
+	case msgHello:
+		if r.u8() != protocolVersion {
+			s.logf("%s: wrong protocol version", key)
+			return
+		}
+		if c == nil {
+			if len(s.clients) >= maxPlayers {
+				s.logf("%s: server full", key)
+				return
+			}
+			c = &remoteClient{id: s.nextID, addr: p.addr}
+			s.nextID++
+			s.clients[key] = c
+			s.world.Join(c.id)
+			s.logf("%s joined as player %d", key, c.id)
+		}
+		c.lastSeen = now
+		// answer every hello in case an earlier welcome was lost
+		s.conn.WriteTo(encodeWelcome(c.id), p.addr)
+	case msgInput:
+		if c == nil {
+			return
+		}
+		in, err := decodeInput(r)
+		if err != nil {
+			s.logf("%s: %v", key, err)
+			return
+		}
+		c.lastSeen = now
+		if in.Ack > c.acked {
+			c.acked = in.Ack
+		}
+		// inputs can arrive out of order, only the newest counts
+		if in.Sequence > c.sequence {
+			c.sequence = in.Sequence
+			s.world.SetControls(c.id, in.Controls)
+		}
+	case msgBye:
+		if c != nil {
+			s.drop(key, c, "left")
+		}
+	}
+}
+
+func (s *Server) drop(key string, c *remoteClient, reason string) {
+	delete(s.clients, key)
+	s.world.Leave(c.id)
+	s.logf("player %d %s", c.id, reason)
+}
+
+func (s *Server) tick(now time.Time) {
+	for key, c := range s.clients {
+		if now.Sub(c.lastSeen) > clientTimeout {
+			s.drop(key, c, "timed out")
+		}
+	}
+
+	s.world.Step()
+	snap := s.world.Snapshot()
+	s.history[snap.Tick%historySize] = snap
+
+	for _, c := range s.clients {
+		s.conn.WriteTo(encodeSnapshot(s.base(c.acked, snap.Tick), snap), c.addr)
+	}
+}
+
+// base returns the snapshot a client acknowledged, if it is still kept.
+func (s *Server) base(acked, tick uint32) *Snapshot {
+	if acked == 0 || tick-acked >= historySize {
+		return nil
+	}
+	if base := s.history[acked%historySize]; base != nil && base.Tick == acked {
+		return base
+	}
+	return nil
+}
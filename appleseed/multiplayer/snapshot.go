@@ -0,0 +1,76 @@
+package multiplayer
+
+import (
+	"sort"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+)
+
+// teleportDistance is how far an apple can move between snapshots before it
+// is treated as respawned rather than interpolated.
+const teleportDistance = 40
+
+type ToonState struct {
+	ID       uint16
+	Position pixel.Vec
+	Size     float64
+	Score    int
+}
+
+type AppleState struct {
+	Position pixel.Vec
+	Grower   bool
+}
+
+// Snapshot is the state of the world at a tick. Toons are sorted by ID.
+type Snapshot struct {
+	Tick   uint32
+	Toons  []ToonState
+	Apples []AppleState
+}
+
+// Time is the snapshot's tick in seconds since the server started.
+func (s *Snapshot) Time() float64 {
+	return float64(s.Tick) * tickSeconds
+}
+
+// Toon finds a toon by ID.
+func (s *Snapshot) Toon(id uint16) (ToonState, bool) {
+	for _, t := range s.Toons {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return ToonState{}, false
+}
+
+// Interpolate blends from a towards b. Toons only in b appear at once and
+// toons only in a are dropped.
+func Interpolate(a, b *Snapshot, t float64) *Snapshot {
+	s := &Snapshot{
+		Tick:   b.Tick,
+		Apples: make([]AppleState, len(b.Apples)),
+	}
+	for _, to := range b.Toons {
+		if from, ok := a.Toon(to.ID); ok {
+			to.Position = games.LerpVec(from.Position, to.Position, t)
+			to.Size = games.Lerp(from.Size, to.Size, t)
+		}
+		s.Toons = append(s.Toons, to)
+	}
+	for i, to := range b.Apples {
+		if i < len(a.Apples) {
+			from := a.Apples[i]
+			if games.Near(from.Position, to.Position, teleportDistance) {
+				to.Position = games.LerpVec(from.Position, to.Position, t)
+			}
+		}
+		s.Apples[i] = to
+	}
+	return s
+}
+
+func sortToons(toons []ToonState) {
+	sort.Slice(toons, func(i, j int) bool { return toons[i].ID < toons[j].ID })
+}
@@ -0,0 +1,167 @@
+package multiplayer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+const (
+	// TickRate is how many times per second the server simulates and sends
+	// snapshots.
+	TickRate    = 30
+	tickSeconds = 1.0 / TickRate
+
+	appleCount = 100
+)
+
+type player struct {
+	toon     *objects.Toon
+	score    int
+	controls objects.Controls
+}
+
+// World is the shared field simulated by the server.
+type World struct {
+	bounds    pixel.Rect
+	moveSpeed float64
+	spawning  *rng.RNG
+	joining   *rng.RNG
+	players   map[uint16]*player
+	apples    []*objects.Apple
+	tick      uint32
+}
+
+func NewWorld(rng *rng.RNG, bounds pixel.Rect, moveSpeed float64) *World {
+	w := &World{
+		bounds:    bounds,
+		moveSpeed: moveSpeed,
+		spawning:  rng.Stream("spawning"),
+		joining:   rng.Stream("players"),
+		players:   make(map[uint16]*player),
+	}
+	for i := 0; i < appleCount; i++ {
+		w.apples = append(w.apples, &objects.Apple{
+			Physics: games.NewPhysicsWithPosition(games.PointInRect(w.spawning, bounds).XY()),
+			Grower:  i%4 != 0,
+		})
+	}
+	return w
+}
+
+// Join adds a toon for a player at a random position.
+func (w *World) Join(id uint16) {
+	if _, ok := w.players[id]; ok {
+		return
+	}
+	w.players[id] = &player{
+		toon: &objects.Toon{
+			Size:    3,
+			Physics: games.NewPhysicsWithPosition(games.PointInRect(w.joining, w.bounds).XY()),
+		},
+	}
+}
+
+func (w *World) Leave(id uint16) {
+	delete(w.players, id)
+}
+
+// SetControls sets the directions a player holds until the next change.
+func (w *World) SetControls(id uint16, controls objects.Controls) {
+	if p, ok := w.players[id]; ok {
+		p.controls = controls
+	}
+}
+
+// ids returns the player ids in order so that every step resolves ties the
+// same way.
+func (w *World) ids() []uint16 {
+	ids := make([]uint16, 0, len(w.players))
+	for id := range w.players {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Step advances the simulation by one tick.
+func (w *World) Step() {
+	dt := tickSeconds
+	ids := w.ids()
+	for _, id := range ids {
+		p := w.players[id]
+		p.toon.Steer(p.controls, w.moveSpeed, dt)
+		p.toon.Update(dt)
+		p.toon.KeepWithin(w.bounds)
+	}
+
+	for _, apple := range w.apples {
+		eaten := false
+		for _, id := range ids {
+			p := w.players[id]
+			if games.Near(apple.Position, p.toon.Position, p.toon.Size) {
+				p.score += int(3 * p.toon.Size)
+				apple.Eat(p.toon)
+				apple.Respawn(w.spawning, p.toon, w.bounds)
+				eaten = true
+				break
+			}
+		}
+		if eaten {
+			continue
+		}
+		if nearest := w.nearest(apple.Position, ids); nearest != nil {
+			apple.Attract(nearest, w.bounds, dt)
+		}
+	}
+	w.tick++
+}
+
+func (w *World) nearest(p pixel.Vec, ids []uint16) *objects.Toon {
+	var nearest *objects.Toon
+	best := math.Inf(1)
+	for _, id := range ids {
+		t := w.players[id].toon
+		if d := games.Distance(p, t.Position); d < best {
+			nearest, best = t, d
+		}
+	}
+	return nearest
+}
+
+// Snapshot captures the state clients see, rounded to the precision it is
+// sent with.
+func (w *World) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Tick:   w.tick,
+		Apples: make([]AppleState, len(w.apples)),
+	}
+	for _, id := range w.ids() {
+		p := w.players[id]
+		s.Toons = append(s.Toons, ToonState{
+			ID:       id,
+			Position: quantizeVec(p.toon.Position),
+			Size:     quantize(p.toon.Size),
+			Score:    p.score,
+		})
+	}
+	for i, apple := range w.apples {
+		s.Apples[i] = AppleState{
+			Position: quantizeVec(apple.Position),
+			Grower:   apple.Grower,
+		}
+	}
+	return s
+}
+
+func quantize(x float64) float64 {
+	return float64(float32(x))
+}
+
+func quantizeVec(v pixel.Vec) pixel.Vec {
+	return pixel.V(quantize(v.X), quantize(v.Y))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/appleseed/multiplayer"
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/network"
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+func fieldBounds(cfg Config) pixel.Rect {
+	return pixel.R(0, 0, float64(cfg.Window.CanvasWidth), float64(cfg.Window.CanvasHeight))
+}
+
+func newRNG(cfg Config) *rng.RNG {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	return rng.New(seed)
+}
+
+// startServer listens on address and simulates the shared field until stop
+// is closed. The server goroutine owns rng from then on, so callers pass it
+// a stream of its own.
+func startServer(cfg Config, address string, rng *rng.RNG, stop <-chan struct{}) (net.Addr, <-chan error, error) {
+	conn, err := network.ListenUDP(address, cfg.Network.link(), rng.Stream("server link"))
+	if err != nil {
+		return nil, nil, err
+	}
+	server := multiplayer.NewServer(conn, multiplayer.NewWorld(rng, fieldBounds(cfg), cfg.MoveSpeed))
+	server.Log = func(format string, args ...interface{}) {
+		fmt.Printf("server: "+format+"\n", args...)
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer conn.Close()
+		done <- server.Run(stop)
+	}()
+	return conn.LocalAddr(), done, nil
+}
+
+// runServer runs a dedicated server without a window until interrupted.
+func runServer(cfg Config) {
+	stop := make(chan struct{})
+	rng := newRNG(cfg)
+	addr, done, err := startServer(cfg, cfg.Network.Address, rng.Stream("server"), stop)
+	if err != nil {
+		exitWith(err, "unable to start server")
+	}
+	fmt.Printf("serving on %s with seed %d\n", addr, rng.Seed())
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	select {
+	case <-interrupt:
+		close(stop)
+		<-done
+	case err := <-done:
+		exitWith(err, "server stopped")
+	}
+}
+
+// runClient plays on a server. In loopback mode it also runs the server, so
+// that latency and loss can be tried out on one machine.
+func runClient(cfg Config) {
+	rng := newRNG(cfg)
+	address := cfg.Network.Address
+	if cfg.Network.Mode == "loopback" {
+		stop := make(chan struct{})
+		defer close(stop)
+		addr, _, err := startServer(cfg, "127.0.0.1:0", rng.Stream("server"), stop)
+		if err != nil {
+			exitWith(err, "unable to start loopback server")
+		}
+		address = addr.String()
+	}
+
+	serverAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		exitWith(err, "unable to resolve %s", address)
+	}
+	conn, err := network.ListenUDP(":0", cfg.Network.link(), rng.Stream("client link"))
+	if err != nil {
+		exitWith(err, "unable to open connection")
+	}
+	client := multiplayer.Dial(conn, serverAddr)
+	defer client.Close()
+
+	winCfg := pixelgl.WindowConfig{
+		Title:  title,
+		Bounds: pixel.R(0, 0, float64(cfg.Window.Width), float64(cfg.Window.Height)),
+		VSync:  true,
+	}
+	win, err := pixelgl.NewWindow(winCfg)
+	if err != nil {
+		exitWith(err, "unable to create window")
+	}
+	win.SetSmooth(true)
+
+	canvas := pixelgl.NewCanvas(fieldBounds(cfg))
+
+	imd := imdraw.New(nil)
+	imd.Precision = 32
+
+	fpsLimit := games.NewFpsLimiter(cfg.Window.MaxFps)
+
+	// objects reused to draw each snapshot
+	apple := &objects.Apple{Physics: games.NewPhysics()}
+	toon := &objects.Toon{Physics: games.NewPhysics()}
+
+	for !win.Closed() {
+		fpsLimit.StartFrame()
+
+		if err := client.Send(readControls(win)); err != nil {
+			exitWith(err, "unable to send input")
+		}
+		if err := client.Err(); err != nil {
+			exitWith(err, "lost connection")
+		}
+
+		id, joined := client.ID()
+		state := client.State()
+
+		imd.Clear()
+		status := "connecting..."
+		if state != nil {
+			for _, a := range state.Apples {
+				apple.Position = a.Position
+				apple.Grower = a.Grower
+				apple.Draw(imd)
+			}
+			status = ""
+			for i, t := range state.Toons {
+				toon.Position = t.Position
+				toon.Size = t.Size
+				toon.Color = playerColor(t.ID == id, i)
+				toon.Draw(imd)
+				name := fmt.Sprintf("player %d", t.ID)
+				if t.ID == id {
+					name = "you"
+				}
+				status += fmt.Sprintf(" | %s: %d", name, t.Score)
+			}
+		} else if joined {
+			status = "waiting for the field..."
+		}
+
+		canvas.Clear(colornames.Black)
+		imd.Draw(canvas)
+		games.DrawCanvasInWindow(colornames.White, win, canvas)
+
+		fpsLimit.WaitForNextFrame()
+		win.SetTitle(fmt.Sprintf("%s %s | fps %.0f", title, status, fpsLimit.CurrentFrameFps()))
+	}
+}
+
+// playerColor draws the local player yellow and everyone else in the rival
+// colors.
+func playerColor(local bool, index int) color.Color {
+	if local {
+		return nil
+	}
+	return rivalColors[index%len(rivalColors)]
+}
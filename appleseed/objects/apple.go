@@ -2,19 +2,46 @@ package objects
 
 import (
 	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/rng"
+	"github.com/explodes/gogames/sprites"
+	"github.com/explodes/gogames/steering"
+	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"golang.org/x/image/colornames"
 )
 
-var _ games.Updater = &Toon{}
-var _ games.Drawer = &Toon{}
+const (
+	// apples within attractRange toon sizes are drawn towards the toon,
+	// speeding up by at most attractMaxForce pixels per second each second
+	// to at most attractMaxSpeed pixels per second
+	attractRange    = 4
+	attractMaxSpeed = 10
+	attractMaxForce = 1.2
+)
+
+var _ games.Updater = &Apple{}
+var _ games.Drawer = &Apple{}
 
 type Apple struct {
 	*games.Physics
 	Grower bool
+	// Sprite, when set, is drawn instead of a plain circle. The IMDraw passed
+	// to Draw must then be created with the sprite sheet's picture.
+	Sprite *sprites.AnimatedSprite
+}
+
+func (a *Apple) Update(dt float64) {
+	a.Physics.Update(dt)
+	if a.Sprite != nil {
+		a.Sprite.Update(dt)
+	}
 }
 
 func (a *Apple) Draw(imd *imdraw.IMDraw) {
+	if a.Sprite != nil {
+		drawSprite(imd, a.Sprite, a.Position, 6)
+		return
+	}
 	if a.Grower {
 		imd.Color = colornames.Red
 	} else {
@@ -23,3 +50,41 @@ func (a *Apple) Draw(imd *imdraw.IMDraw) {
 	imd.Push(a.Position)
 	imd.Circle(3, 0)
 }
+
+// Attract pulls the apple towards a toon that is close enough, keeping it
+// within bounds. It reports whether the toon was close enough.
+func (a *Apple) Attract(t *Toon, bounds pixel.Rect, dt float64) bool {
+	if !games.Near(a.Position, t.Position, attractRange*t.Size) {
+		return false
+	}
+	pull := steering.Agent{Physics: a.Physics, MaxSpeed: attractMaxSpeed, MaxForce: attractMaxForce}
+	pull.Apply(pull.Seek(t.Position))
+	pull.Update(dt)
+	if a.Sprite != nil {
+		a.Sprite.Update(dt)
+	}
+	a.Position = games.LimitWithinRect(a.Position, bounds)
+	return true
+}
+
+// Respawn moves the apple to a random spot away from the toon and stops it.
+func (a *Apple) Respawn(rng *rng.RNG, t *Toon, bounds pixel.Rect) {
+	for i := 0; i < 10; i++ {
+		newPos := games.PointInRect(rng, bounds)
+		if games.Distance(t.Position, newPos) > t.Size {
+			a.Position = newPos
+			break
+		}
+	}
+	a.Velocity = pixel.ZV
+	a.Acceleration = pixel.ZV
+}
+
+// Eat grows or shrinks the toon depending on the apple.
+func (a *Apple) Eat(t *Toon) {
+	if a.Grower {
+		t.Grow()
+	} else {
+		t.Shrink()
+	}
+}
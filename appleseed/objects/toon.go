@@ -2,8 +2,11 @@ package objects
 
 import (
 	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/sprites"
+	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"golang.org/x/image/colornames"
+	"image/color"
 	"math"
 )
 
@@ -13,15 +16,30 @@ var _ games.Drawer = &Toon{}
 type Toon struct {
 	*games.Physics
 	Size float64
+	// Color is the color of the plain circle, yellow when nil.
+	Color color.Color
+	// Sprite, when set, is drawn instead of a plain circle. The IMDraw passed
+	// to Draw must then be created with the sprite sheet's picture.
+	Sprite *sprites.AnimatedSprite
 }
 
 func (t *Toon) Update(dt float64) {
 	t.Physics.Update(dt)
+	if t.Sprite != nil {
+		t.Sprite.Update(dt)
+	}
 	//t.Velocity = t.Velocity.Scaled(100 * dt)
 }
 
 func (t *Toon) Draw(imd *imdraw.IMDraw) {
+	if t.Sprite != nil {
+		drawSprite(imd, t.Sprite, t.Position, 2*t.Size)
+		return
+	}
 	imd.Color = colornames.Yellow
+	if t.Color != nil {
+		imd.Color = t.Color
+	}
 	imd.Push(t.Position)
 	imd.Circle(t.Size, 0)
 }
@@ -30,6 +48,65 @@ func (t *Toon) Move(x, y float64) {
 	t.Force(x/t.Size*2, y/t.Size*2)
 }
 
+// Controls are the directions a player is holding.
+type Controls struct {
+	Up, Down, Left, Right bool
+}
+
+// Steer pushes the toon in the held directions, braking hard when pushing
+// against its current velocity.
+func (t *Toon) Steer(c Controls, moveSpeed, dt float64) {
+	var dx, dy float64
+
+	if c.Up {
+		if t.Velocity.Y < 0 {
+			dy += 200 * dt * moveSpeed
+		}
+		dy += moveSpeed
+	}
+	if c.Down {
+		if t.Velocity.Y > 0 {
+			dy -= 200 * dt * moveSpeed
+		}
+		dy -= moveSpeed
+	}
+	if c.Left {
+		if t.Velocity.X > 0 {
+			dx -= 200 * dt * moveSpeed
+		}
+		dx -= moveSpeed
+	}
+	if c.Right {
+		if t.Velocity.X < 0 {
+			dx += 200 * dt * moveSpeed
+		}
+		dx += moveSpeed
+	}
+	t.Move(dt*dx, dt*dy)
+}
+
+// KeepWithin stops the toon at the edges of bounds.
+func (t *Toon) KeepWithin(bounds pixel.Rect) {
+	if t.Position.X < bounds.Min.X {
+		t.Position.X = bounds.Min.X
+		t.Velocity.X = 0
+		t.Acceleration.X = 0
+	} else if t.Position.X > bounds.Max.X {
+		t.Position.X = bounds.Max.X
+		t.Velocity.X = 0
+		t.Acceleration.X = 0
+	}
+	if t.Position.Y < bounds.Min.Y {
+		t.Position.Y = bounds.Min.Y
+		t.Velocity.Y = 0
+		t.Acceleration.Y = 0
+	} else if t.Position.Y > bounds.Max.Y {
+		t.Position.Y = bounds.Max.Y
+		t.Velocity.Y = 0
+		t.Acceleration.Y = 0
+	}
+}
+
 func (t *Toon) Grow() {
 	t.Size = math.Min(100, t.Size+0.5)
 }
@@ -37,3 +114,11 @@ func (t *Toon) Grow() {
 func (t *Toon) Shrink() {
 	t.Size = math.Max(3, t.Size-0.5)
 }
+
+// drawSprite draws sprite centered on position, scaled to fit diameter.
+func drawSprite(imd *imdraw.IMDraw, sprite *sprites.AnimatedSprite, position pixel.Vec, diameter float64) {
+	size := sprite.FrameSize()
+	scale := diameter / math.Max(size.X, size.Y)
+	sprite.Matrix = pixel.IM.Scaled(pixel.ZV, scale).Moved(position)
+	sprite.Draw(imd)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/rng"
+	"github.com/explodes/gogames/steering"
+	"github.com/faiface/pixel"
+	"golang.org/x/image/colornames"
+)
+
+var rivalColors = []color.Color{
+	colornames.Orangered,
+	colornames.Deepskyblue,
+	colornames.Violet,
+	colornames.Limegreen,
+}
+
+// difficulty sets how fast rivals move and how often they change their mind.
+type difficulty struct {
+	maxSpeed float64
+	maxForce float64
+	// reaction is the number of seconds between choosing targets.
+	reaction float64
+}
+
+var difficulties = map[string]difficulty{
+	"easy":   {maxSpeed: 80, maxForce: 200, reaction: 0.8},
+	"normal": {maxSpeed: 130, maxForce: 350, reaction: 0.35},
+	"hard":   {maxSpeed: 190, maxForce: 600, reaction: 0.1},
+}
+
+// field is what rivals can see.
+type field struct {
+	player *objects.Toon
+	apples []*objects.Apple
+}
+
+// Strategy decides what a rival goes after and how it gets there.
+type Strategy interface {
+	Name() string
+	// Choose picks the apple to head for, or nil to stand still.
+	Choose(self *objects.Toon, f field) *objects.Apple
+	// Steer returns the force that moves the rival towards target.
+	Steer(agent steering.Agent, target *objects.Apple, f field) pixel.Vec
+}
+
+var strategies = map[string]func() Strategy{
+	"greedy":    func() Strategy { return greedy{} },
+	"cautious":  func() Strategy { return cautious{} },
+	"intercept": func() Strategy { return intercept{} },
+}
+
+func parseStrategies(list string) ([]Strategy, error) {
+	var parsed []Strategy
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		newStrategy, ok := strategies[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown rival strategy %q", name)
+		}
+		parsed = append(parsed, newStrategy())
+	}
+	return parsed, nil
+}
+
+// nearestGrower returns the grower closest to p by cost, or nil.
+func nearestGrower(p pixel.Vec, apples []*objects.Apple, cost func(apple *objects.Apple) float64) *objects.Apple {
+	var best *objects.Apple
+	bestCost := math.Inf(1)
+	for _, apple := range apples {
+		if !apple.Grower {
+			continue
+		}
+		c := games.Distance(p, apple.Position)
+		if cost != nil {
+			c += cost(apple)
+		}
+		if c < bestCost {
+			best, bestCost = apple, c
+		}
+	}
+	return best
+}
+
+// greedy always goes for the nearest grower.
+type greedy struct{}
+
+func (greedy) Name() string {
+	return "Greedy"
+}
+
+func (greedy) Choose(self *objects.Toon, f field) *objects.Apple {
+	return nearestGrower(self.Position, f.apples, nil)
+}
+
+func (greedy) Steer(agent steering.Agent, target *objects.Apple, f field) pixel.Vec {
+	return agent.Seek(target.Position)
+}
+
+// cautious goes for growers away from shrinkers and steers around shrinkers
+// on the way.
+type cautious struct{}
+
+const cautiousRadius = 30
+
+func (cautious) Name() string {
+	return "Cautious"
+}
+
+func (cautious) Choose(self *objects.Toon, f field) *objects.Apple {
+	return nearestGrower(self.Position, f.apples, func(apple *objects.Apple) float64 {
+		penalty := 0.0
+		for _, other := range f.apples {
+			if !other.Grower && games.Near(other.Position, apple.Position, cautiousRadius) {
+				penalty += cautiousRadius
+			}
+		}
+		return penalty
+	})
+}
+
+func (cautious) Steer(agent steering.Agent, target *objects.Apple, f field) pixel.Vec {
+	forces := []steering.Weighted{{Force: agent.Seek(target.Position), Weight: 1}}
+	for _, apple := range f.apples {
+		if !apple.Grower {
+			forces = append(forces, steering.Weighted{Force: agent.Flee(apple.Position, cautiousRadius), Weight: 1.5})
+		}
+	}
+	return steering.Blend(agent.MaxForce, forces...)
+}
+
+// intercept races the player to the grower the player is heading for.
+type intercept struct{}
+
+// interceptLead is how many seconds ahead the player's position is guessed.
+const interceptLead = 0.5
+
+func (intercept) Name() string {
+	return "Interceptor"
+}
+
+func (intercept) Choose(self *objects.Toon, f field) *objects.Apple {
+	heading := f.player.Position.Add(f.player.Velocity.Scaled(interceptLead))
+	return nearestGrower(heading, f.apples, nil)
+}
+
+func (intercept) Steer(agent steering.Agent, target *objects.Apple, f field) pixel.Vec {
+	return agent.Seek(target.Position)
+}
+
+var _ games.Updater = &Rival{}
+
+// Rival is a computer controlled toon competing for apples.
+type Rival struct {
+	Toon     *objects.Toon
+	Strategy Strategy
+	Score    int
+
+	level  difficulty
+	target *objects.Apple
+	think  float64
+}
+
+func newRivals(rng *rng.RNG, bounds pixel.Rect, strategies []Strategy, level difficulty) []*Rival {
+	spawning := rng.Stream("rivals")
+	rivals := make([]*Rival, len(strategies))
+	for i, strategy := range strategies {
+		rivals[i] = &Rival{
+			Toon: &objects.Toon{
+				Size:    3,
+				Color:   rivalColors[i%len(rivalColors)],
+				Physics: games.NewPhysicsWithPosition(games.PointInRect(spawning, bounds).XY()),
+			},
+			Strategy: strategy,
+			level:    level,
+			// spread out thinking so rivals don't all turn at once
+			think: spawning.Range(0, level.reaction),
+		}
+	}
+	return rivals
+}
+
+// Think picks a new target whenever the rival's reaction time has passed.
+func (r *Rival) Think(dt float64, f field) {
+	r.think -= dt
+	if r.think > 0 && r.target != nil {
+		return
+	}
+	r.think = r.level.reaction
+	r.target = r.Strategy.Choose(r.Toon, f)
+}
+
+// Steer applies the strategy's force towards the current target.
+func (r *Rival) Steer(f field) {
+	if r.target == nil {
+		return
+	}
+	agent := r.agent()
+	agent.Apply(r.Strategy.Steer(agent, r.target, f))
+}
+
+func (r *Rival) agent() steering.Agent {
+	return steering.Agent{
+		Physics:  r.Toon.Physics,
+		MaxSpeed: r.level.maxSpeed,
+		MaxForce: r.level.maxForce,
+	}
+}
+
+func (r *Rival) Update(dt float64) {
+	r.agent().Update(dt)
+}
+
+// writeResults compares the player's score with the rivals', best first.
+func writeResults(w io.Writer, score int, rivals []*Rival) {
+	type result struct {
+		name  string
+		score int
+	}
+	results := []result{{"You", score}}
+	for _, r := range rivals {
+		results = append(results, result{r.Strategy.Name(), r.Score})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	switch {
+	case len(rivals) == 0:
+		fmt.Fprintf(w, "You scored %d\n", score)
+		return
+	case results[0].name == "You" && results[1].score < score:
+		fmt.Fprint(w, "You win!\n")
+	case results[0].score == score:
+		fmt.Fprint(w, "It's a tie!\n")
+	default:
+		fmt.Fprintf(w, "%s wins!\n", results[0].name)
+	}
+	for i, result := range results {
+		fmt.Fprintf(w, "%d. %-12s %6d\n", i+1, result.name, result.score)
+	}
+}
+
+// rivalScores formats the rivals' scores for the window title.
+func rivalScores(rivals []*Rival) string {
+	var b strings.Builder
+	for _, r := range rivals {
+		fmt.Fprintf(&b, " | %s: %d", strings.ToLower(r.Strategy.Name()), r.Score)
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/appleseed/objects"
+	"github.com/explodes/gogames/persistence"
+)
+
+const (
+	saveVersion  = 1
+	autosaveSlot = "autosave"
+)
+
+type savedGame struct {
+	Toon   savedToon    `json:"toon"`
+	Apples []savedApple `json:"apples"`
+	Score  int          `json:"score"`
+}
+
+type savedToon struct {
+	Physics games.Physics `json:"physics"`
+	Size    float64       `json:"size"`
+}
+
+type savedApple struct {
+	Physics games.Physics `json:"physics"`
+	Grower  bool          `json:"grower"`
+}
+
+func newSaveStore() (*persistence.Store, error) {
+	return persistence.NewStore("appleseed", saveVersion, persistence.JSON)
+}
+
+func saveGame(store *persistence.Store, toon *objects.Toon, apples []*objects.Apple, score int) error {
+	game := savedGame{
+		Toon: savedToon{
+			Physics: *toon.Physics,
+			Size:    toon.Size,
+		},
+		Score: score,
+	}
+	for _, apple := range apples {
+		game.Apples = append(game.Apples, savedApple{
+			Physics: *apple.Physics,
+			Grower:  apple.Grower,
+		})
+	}
+	return store.Save(autosaveSlot, game)
+}
+
+func loadGame(store *persistence.Store) (*objects.Toon, []*objects.Apple, int, error) {
+	var game savedGame
+	if err := store.Load(autosaveSlot, &game); err != nil {
+		return nil, nil, 0, err
+	}
+
+	toonPhysics := game.Toon.Physics
+	toon := &objects.Toon{
+		Physics: &toonPhysics,
+		Size:    game.Toon.Size,
+	}
+
+	apples := make([]*objects.Apple, 0, len(game.Apples))
+	for _, saved := range game.Apples {
+		physics := saved.Physics
+		apples = append(apples, &objects.Apple{
+			Physics: &physics,
+			Grower:  saved.Grower,
+		})
+	}
+	return toon, apples, game.Score, nil
+}
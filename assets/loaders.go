@@ -0,0 +1,137 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/wav"
+	"github.com/faiface/pixel"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+const (
+	kindBytes   = "bytes"
+	kindPicture = "picture"
+	kindFont    = "font"
+	kindShader  = "shader"
+	kindSound   = "sound"
+)
+
+// Bytes loads the raw contents of a file.
+func (m *Manager) Bytes(name string) ([]byte, error) {
+	value, err := m.load(name, kindBytes, decodeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return value.([]byte), nil
+}
+
+// JSON loads a JSON file and unmarshals it into v.
+func (m *Manager) JSON(name string, v interface{}) error {
+	data, err := m.Bytes(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unable to decode asset %s: %v", name, err)
+	}
+	return nil
+}
+
+// Picture loads a PNG or JPEG image.
+func (m *Manager) Picture(name string) (*pixel.PictureData, error) {
+	value, err := m.load(name, kindPicture, decodePicture)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*pixel.PictureData), nil
+}
+
+// Font loads a TrueType font and returns a face of the given point size.
+func (m *Manager) Font(name string, size float64) (font.Face, error) {
+	value, err := m.load(name, kindFont, decodeFont)
+	if err != nil {
+		return nil, err
+	}
+	return truetype.NewFace(value.(*truetype.Font), &truetype.Options{Size: size}), nil
+}
+
+// Shader loads GLSL source. The source is NUL-terminated so that it can be
+// passed straight to gl.Strs.
+func (m *Manager) Shader(name string) (string, error) {
+	value, err := m.load(name, kindShader, decodeShader)
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// Sound loads a WAV or MP3 file fully into memory.
+func (m *Manager) Sound(name string) (*beep.Buffer, error) {
+	decode := func(data []byte) (interface{}, error) {
+		return decodeSound(path.Ext(name), data)
+	}
+	value, err := m.load(name, kindSound, decode)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*beep.Buffer), nil
+}
+
+func decodeBytes(data []byte) (interface{}, error) {
+	return data, nil
+}
+
+func decodePicture(data []byte) (interface{}, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return pixel.PictureDataFromImage(img), nil
+}
+
+func decodeFont(data []byte) (interface{}, error) {
+	return truetype.Parse(data)
+}
+
+func decodeShader(data []byte) (interface{}, error) {
+	source := string(data)
+	if !strings.HasSuffix(source, "\x00") {
+		source += "\x00"
+	}
+	return source, nil
+}
+
+func decodeSound(ext string, data []byte) (interface{}, error) {
+	var (
+		streamer beep.StreamSeekCloser
+		format   beep.Format
+		err      error
+	)
+	switch strings.ToLower(ext) {
+	case ".wav":
+		streamer, format, err = wav.Decode(bytes.NewReader(data))
+	case ".mp3":
+		streamer, format, err = mp3.Decode(ioutil.NopCloser(bytes.NewReader(data)))
+	default:
+		return nil, fmt.Errorf("unsupported sound format %q", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer streamer.Close()
+
+	buffer := beep.NewBuffer(format)
+	buffer.Append(streamer)
+	return buffer, nil
+}
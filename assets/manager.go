@@ -0,0 +1,209 @@
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/explodes/gogames"
+)
+
+const defaultPollInterval = 0.5
+
+// decoder turns the raw bytes of a file into a cached asset value.
+type decoder func(data []byte) (interface{}, error)
+
+type entry struct {
+	kind     string
+	value    interface{}
+	decode   decoder
+	refs     int
+	modTime  time.Time
+	onReload []func(value interface{})
+}
+
+// Manager loads assets from a filesystem, caches them by name and
+// reference-counts them. A Manager created with NewDir watches its files and
+// reloads them when they change on disk.
+type Manager struct {
+	fsys fs.FS
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	watch        bool
+	pollInterval float64
+	sincePoll    float64
+}
+
+var _ games.Updater = &Manager{}
+
+// New creates a Manager over fsys, which is typically an embed.FS.
+func New(fsys fs.FS) *Manager {
+	return &Manager{
+		fsys:         fsys,
+		entries:      make(map[string]*entry),
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// NewDir creates a Manager over a directory on disk with hot reload enabled.
+func NewDir(dir string) *Manager {
+	m := New(os.DirFS(dir))
+	m.SetHotReload(true)
+	return m
+}
+
+// SetHotReload enables or disables polling the filesystem for changes.
+func (m *Manager) SetHotReload(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watch = enabled
+}
+
+// SetPollInterval sets how often, in seconds, Update checks files for changes.
+func (m *Manager) SetPollInterval(seconds float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollInterval = seconds
+}
+
+// OnReload registers fn to be called with the new value each time the named
+// asset is reloaded.
+func (m *Manager) OnReload(name string, fn func(value interface{})) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.entries[name]; ok {
+		e.onReload = append(e.onReload, fn)
+	}
+}
+
+// Release drops one reference to the named asset, evicting it from the cache
+// when no references remain.
+func (m *Manager) Release(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[name]
+	if !ok {
+		return
+	}
+	e.refs--
+	if e.refs <= 0 {
+		delete(m.entries, name)
+	}
+}
+
+// Refs returns the number of outstanding references to the named asset.
+func (m *Manager) Refs(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.entries[name]; ok {
+		return e.refs
+	}
+	return 0
+}
+
+// Update polls for changed files when hot reload is enabled.
+func (m *Manager) Update(dt float64) {
+	m.mu.Lock()
+	if !m.watch {
+		m.mu.Unlock()
+		return
+	}
+	m.sincePoll += dt
+	if m.sincePoll < m.pollInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.sincePoll = 0
+	m.mu.Unlock()
+
+	if err := m.Reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "assets: %v\n", err)
+	}
+}
+
+// Reload re-reads every cached asset whose file has changed since it was
+// loaded. Assets that fail to decode keep their previous value.
+func (m *Manager) Reload() error {
+	type reloaded struct {
+		value     interface{}
+		callbacks []func(value interface{})
+	}
+	var (
+		changed  []reloaded
+		firstErr error
+	)
+
+	m.mu.Lock()
+	for name, e := range m.entries {
+		info, err := fs.Stat(m.fsys, name)
+		if err != nil || !info.ModTime().After(e.modTime) {
+			continue
+		}
+		value, modTime, err := m.read(name, e.decode)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		e.value = value
+		e.modTime = modTime
+		changed = append(changed, reloaded{value: value, callbacks: e.onReload})
+	}
+	m.mu.Unlock()
+
+	for _, r := range changed {
+		for _, fn := range r.callbacks {
+			fn(r.value)
+		}
+	}
+	return firstErr
+}
+
+// load returns the cached value for name, decoding and caching it first if
+// needed. Each call adds a reference that must be dropped with Release.
+func (m *Manager) load(name, kind string, decode decoder) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[name]; ok {
+		if e.kind != kind {
+			return nil, fmt.Errorf("asset %s already loaded as %s, not %s", name, e.kind, kind)
+		}
+		e.refs++
+		return e.value, nil
+	}
+
+	value, modTime, err := m.read(name, decode)
+	if err != nil {
+		return nil, err
+	}
+	m.entries[name] = &entry{
+		kind:    kind,
+		value:   value,
+		decode:  decode,
+		refs:    1,
+		modTime: modTime,
+	}
+	return value, nil
+}
+
+func (m *Manager) read(name string, decode decoder) (interface{}, time.Time, error) {
+	var modTime time.Time
+	if info, err := fs.Stat(m.fsys, name); err == nil {
+		modTime = info.ModTime()
+	}
+	data, err := fs.ReadFile(m.fsys, name)
+	if err != nil {
+		return nil, modTime, fmt.Errorf("unable to read asset %s: %v", name, err)
+	}
+	value, err := decode(data)
+	if err != nil {
+		return nil, modTime, fmt.Errorf("unable to decode asset %s: %v", name, err)
+	}
+	return value, modTime, nil
+}
@@ -0,0 +1,194 @@
+package colors
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// HSV is a color as hue in degrees [0, 360), saturation and value in [0, 1].
+type HSV struct {
+	H, S, V, A float64
+}
+
+// HSL is a color as hue in degrees [0, 360), saturation and lightness in
+// [0, 1].
+type HSL struct {
+	H, S, L, A float64
+}
+
+// OKLab is a color in the Oklab perceptual color space, where equal
+// distances look like equal differences in color.
+type OKLab struct {
+	L, A, B float64
+	Alpha   float64
+}
+
+// hue returns the hue in degrees, the maximum and minimum components.
+func hue(c pixel.RGBA) (h, max, min float64) {
+	max = math.Max(c.R, math.Max(c.G, c.B))
+	min = math.Min(c.R, math.Min(c.G, c.B))
+	d := max - min
+	switch {
+	case d == 0:
+		h = 0
+	case max == c.R:
+		h = math.Mod((c.G-c.B)/d, 6)
+	case max == c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	return WrapHue(h * 60), max, min
+}
+
+// WrapHue wraps a hue in degrees into [0, 360).
+func WrapHue(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// fromHue builds a color from a hue, chroma and the amount added to every
+// component.
+func fromHue(h, chroma, m, a float64) pixel.RGBA {
+	h = WrapHue(h) / 60
+	x := chroma * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return pixel.RGBA{R: r + m, G: g + m, B: b + m, A: a}
+}
+
+func ToHSV(c pixel.RGBA) HSV {
+	h, max, min := hue(c)
+	s := 0.0
+	if max > 0 {
+		s = (max - min) / max
+	}
+	return HSV{H: h, S: s, V: max, A: c.A}
+}
+
+func (c HSV) RGBA() pixel.RGBA {
+	chroma := c.V * c.S
+	return fromHue(c.H, chroma, c.V-chroma, c.A)
+}
+
+func ToHSL(c pixel.RGBA) HSL {
+	h, max, min := hue(c)
+	l := (max + min) / 2
+	s := 0.0
+	if d := max - min; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return HSL{H: h, S: s, L: l, A: c.A}
+}
+
+func (c HSL) RGBA() pixel.RGBA {
+	chroma := (1 - math.Abs(2*c.L-1)) * c.S
+	return fromHue(c.H, chroma, c.L-chroma/2, c.A)
+}
+
+func toLinear(x float64) float64 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+func fromLinear(x float64) float64 {
+	if x <= 0.0031308 {
+		return 12.92 * x
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+func ToOKLab(c pixel.RGBA) OKLab {
+	r, g, b := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return OKLab{
+		L:     0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A:     1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B:     0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+		Alpha: c.A,
+	}
+}
+
+// RGBA converts back to RGB, clamping colors outside of the RGB gamut.
+func (c OKLab) RGBA() pixel.RGBA {
+	l := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	m := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	s := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return pixel.RGBA{
+		R: clamp(fromLinear(+4.0767416621*l - 3.3077115913*m + 0.2309699292*s)),
+		G: clamp(fromLinear(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s)),
+		B: clamp(fromLinear(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s)),
+		A: c.Alpha,
+	}
+}
+
+// Distance is the perceptual difference between two colors.
+func Distance(c1, c2 pixel.RGBA) float64 {
+	a, b := ToOKLab(c1), ToOKLab(c2)
+	dl, da, db := a.L-b.L, a.A-b.A, a.B-b.B
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// Hex parses colors written as "#rgb", "#rrggbb" or "#rrggbbaa". It returns
+// black for anything else.
+func Hex(s string) pixel.RGBA {
+	if len(s) > 0 && s[0] == '#' {
+		s = s[1:]
+	}
+	digit := func(i int) float64 {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			return float64(c - '0')
+		case c >= 'a' && c <= 'f':
+			return float64(c-'a') + 10
+		case c >= 'A' && c <= 'F':
+			return float64(c-'A') + 10
+		}
+		return 0
+	}
+	switch len(s) {
+	case 3:
+		return pixel.RGB(digit(0)/15, digit(1)/15, digit(2)/15)
+	case 6, 8:
+		c := pixel.RGB(
+			(digit(0)*16+digit(1))/255,
+			(digit(2)*16+digit(3))/255,
+			(digit(4)*16+digit(5))/255,
+		)
+		if len(s) == 8 {
+			c.A = (digit(6)*16 + digit(7)) / 255
+		}
+		return c
+	}
+	return pixel.RGB(0, 0, 0)
+}
+
+func clamp(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
@@ -0,0 +1,142 @@
+package colors
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+// Palette is an ordered set of colors.
+type Palette []pixel.RGBA
+
+var palettes = map[string]Palette{
+	"fire": {
+		Hex("#ffffb2"),
+		Hex("#fecc5c"),
+		Hex("#fd8d3c"),
+		Hex("#f03b20"),
+		Hex("#bd0026"),
+	},
+	"pastel": {
+		Hex("#fbb4ae"),
+		Hex("#b3cde3"),
+		Hex("#ccebc5"),
+		Hex("#decbe4"),
+		Hex("#fed9a6"),
+		Hex("#ffffcc"),
+	},
+	"pico8": {
+		Hex("#000000"), Hex("#1d2b53"), Hex("#7e2553"), Hex("#008751"),
+		Hex("#ab5236"), Hex("#5f574f"), Hex("#c2c3c7"), Hex("#fff1e8"),
+		Hex("#ff004d"), Hex("#ffa300"), Hex("#ffec27"), Hex("#00e436"),
+		Hex("#29adff"), Hex("#83769c"), Hex("#ff77a8"), Hex("#ffccaa"),
+	},
+	"gameboy": {
+		Hex("#0f380f"),
+		Hex("#306230"),
+		Hex("#8bac0f"),
+		Hex("#9bbc0f"),
+	},
+}
+
+// Named returns a copy of a built-in palette.
+func Named(name string) (Palette, error) {
+	p, ok := palettes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette %q", name)
+	}
+	return append(Palette(nil), p...), nil
+}
+
+// Names returns the names of the built-in palettes.
+func Names() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Register adds a named palette, replacing any palette of the same name.
+func Register(name string, p Palette) {
+	palettes[name] = append(Palette(nil), p...)
+}
+
+// At returns the color at index i, wrapping around the palette.
+func (p Palette) At(i int) pixel.RGBA {
+	i %= len(p)
+	if i < 0 {
+		i += len(p)
+	}
+	return p[i]
+}
+
+// Nearest returns the index of the color perceptually closest to c.
+func (p Palette) Nearest(c pixel.RGBA) int {
+	best, bestDistance := -1, math.Inf(1)
+	for i, candidate := range p {
+		if d := Distance(c, candidate); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+// Hues creates n evenly spaced, fully saturated hues starting at start
+// degrees.
+func Hues(n int, start, saturation, value float64) Palette {
+	p := make(Palette, n)
+	for i := range p {
+		p[i] = HSV{H: start + 360*float64(i)/float64(n), S: saturation, V: value, A: 1}.RGBA()
+	}
+	return p
+}
+
+// RandomNice returns a random bright color: a random direction in RGB space
+// scaled to unit length.
+func RandomNice(rng *rng.RNG) pixel.RGBA {
+	for {
+		r, g, b := rng.Float64(), rng.Float64(), rng.Float64()
+		magnitude := math.Sqrt(r*r + g*g + b*b)
+		if magnitude != 0 {
+			return pixel.RGB(r/magnitude, g/magnitude, b/magnitude)
+		}
+	}
+}
+
+// AssignGrid picks a palette index for every cell of a columns×rows grid,
+// indexed x+y*columns, so that no two cells sharing an edge get the same
+// index. That takes at least 2 colors; with fewer every cell gets index 0.
+// With three or more colors every color is used and no row or column is a
+// single color.
+func AssignGrid(columns, rows, colors int) []int {
+	indexes := make([]int, columns*rows)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			var index int
+			if colors == 2 {
+				index = (x + y) % 2
+			} else if colors > 2 {
+				// neighbours differ by 1 across and 2 down, neither of which
+				// is a multiple of colors
+				index = (x + 2*y) % colors
+			}
+			indexes[x+y*columns] = index
+		}
+	}
+	return indexes
+}
+
+// ColorGrid is AssignGrid resolved to the palette's colors.
+func (p Palette) ColorGrid(columns, rows int) []pixel.RGBA {
+	indexes := AssignGrid(columns, rows, len(p))
+	grid := make([]pixel.RGBA, len(indexes))
+	for i, index := range indexes {
+		grid[i] = p[index]
+	}
+	return grid
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/explodes/gogames/persistence"
+)
+
+// Validator is implemented by configs that need checks beyond min and max tags.
+type Validator interface {
+	Validate() error
+}
+
+// Path returns the default config file of a game, which lives next to its
+// saved games.
+func Path(game string) (string, error) {
+	dir, err := persistence.Dir(game)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load fills cfg, a pointer to a struct already holding the defaults. Values
+// are applied in order from the JSON config file, environment variables and
+// finally command-line flags from args.
+//
+// A field named window.width in game appleseed is set by the "window.width"
+// key of the file's "window" object, by APPLESEED_WINDOW_WIDTH and by
+// -window.width. The file is read from Path(game), or from -config or
+// APPLESEED_CONFIG when given.
+func Load(game string, cfg interface{}, args []string) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a pointer to a struct, got %T", cfg)
+	}
+	envPrefix := envName(game) + "_"
+	all, err := fields("", envPrefix, v.Elem())
+	if err != nil {
+		return err
+	}
+
+	set := flag.NewFlagSet(game, flag.ContinueOnError)
+	configFile := set.String("config", "", "config file (env "+envPrefix+"CONFIG)")
+	pending := make(map[*field]string)
+	for _, f := range all {
+		set.Var(&flagValue{field: f, pending: pending}, f.name, f.help+" (env "+f.env+")")
+	}
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	path, explicit := *configFile, true
+	if path == "" {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	if path == "" {
+		explicit = false
+		if path, err = Path(game); err != nil {
+			return err
+		}
+	}
+	if err := loadFile(path, cfg, explicit); err != nil {
+		return err
+	}
+
+	for _, f := range all {
+		if s, ok := os.LookupEnv(f.env); ok {
+			if err := f.set(s); err != nil {
+				return fmt.Errorf("%s: %v", f.env, err)
+			}
+		}
+	}
+
+	for _, f := range all {
+		if s, ok := pending[f]; ok {
+			if err := f.set(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range all {
+		if err := f.validate(); err != nil {
+			return err
+		}
+	}
+	if validator, ok := cfg.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// loadFile decodes a JSON config file into cfg. A missing file is only an
+// error if it was asked for explicitly.
+func loadFile(path string, cfg interface{}, explicit bool) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read config: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return nil
+}
+
+// flagValue defers setting a field until the file and environment have been
+// applied, so that flags take precedence.
+type flagValue struct {
+	field   *field
+	pending map[*field]string
+}
+
+func (fv *flagValue) String() string {
+	if fv.field == nil {
+		return ""
+	}
+	return fv.field.String()
+}
+
+func (fv *flagValue) Set(s string) error {
+	fv.pending[fv.field] = s
+	return nil
+}
+
+func (fv *flagValue) IsBoolFlag() bool {
+	return fv.field.value.Kind() == reflect.Bool
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// field is a single configurable value within a config struct.
+type field struct {
+	name  string // dotted flag name, e.g. window.width
+	env   string // environment variable, e.g. APPLESEED_WINDOW_WIDTH
+	help  string
+	min   string
+	max   string
+	value reflect.Value
+}
+
+// fields walks a config struct, flattening nested structs into dotted names.
+// Names come from json tags, falling back to the lowercased field name.
+func fields(prefix, envPrefix string, v reflect.Value) ([]*field, error) {
+	var result []*field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := fieldName(sf)
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			nested, err := fields(prefix+name+".", envPrefix+envName(name)+"_", fv)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, nested...)
+			continue
+		}
+		switch sf.Type.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.String:
+		default:
+			return nil, fmt.Errorf("config field %s has unsupported type %s", sf.Name, sf.Type)
+		}
+		result = append(result, &field{
+			name:  prefix + name,
+			env:   envPrefix + envName(name),
+			help:  sf.Tag.Get("help"),
+			min:   sf.Tag.Get("min"),
+			max:   sf.Tag.Get("max"),
+			value: fv,
+		})
+	}
+	return result, nil
+}
+
+func fieldName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(sf.Name)
+}
+
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// set parses s into the field.
+func (f *field) set(s string) error {
+	switch f.value.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%s: invalid bool %q", f.name, s)
+		}
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", f.name, s)
+		}
+		f.value.SetInt(i)
+	case reflect.Float64:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", f.name, s)
+		}
+		f.value.SetFloat(x)
+	case reflect.String:
+		f.value.SetString(s)
+	}
+	return nil
+}
+
+func (f *field) String() string {
+	switch f.value.Kind() {
+	case reflect.Float64:
+		return strconv.FormatFloat(f.value.Float(), 'g', -1, 64)
+	default:
+		return fmt.Sprint(f.value.Interface())
+	}
+}
+
+// validate checks the field against its min and max tags.
+func (f *field) validate() error {
+	var x float64
+	switch f.value.Kind() {
+	case reflect.Int, reflect.Int64:
+		x = float64(f.value.Int())
+	case reflect.Float64:
+		x = f.value.Float()
+	default:
+		return nil
+	}
+	if f.min != "" {
+		min, err := strconv.ParseFloat(f.min, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid min tag %q", f.name, f.min)
+		}
+		if x < min {
+			return fmt.Errorf("%s must be at least %s, got %s", f.name, f.min, f)
+		}
+	}
+	if f.max != "" {
+		max, err := strconv.ParseFloat(f.max, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid max tag %q", f.name, f.max)
+		}
+		if x > max {
+			return fmt.Errorf("%s must be at most %s, got %s", f.name, f.max, f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package config
+
+// Window is the window and canvas setup shared by the pixel games.
+type Window struct {
+	Width        int `json:"width" help:"window width in pixels" min:"1"`
+	Height       int `json:"height" help:"window height in pixels" min:"1"`
+	CanvasWidth  int `json:"canvas_width" help:"canvas width in pixels" min:"1"`
+	CanvasHeight int `json:"canvas_height" help:"canvas height in pixels" min:"1"`
+	MaxFps       int `json:"max_fps" help:"frame rate limit" min:"1" max:"1000"`
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"reflect"
+	"sync"
+)
+
+type handler struct {
+	id uint64
+	fn func(event interface{})
+}
+
+type queued struct {
+	eventType reflect.Type
+	event     interface{}
+}
+
+// Bus delivers typed events to subscribers. Events are queued by Publish and
+// delivered in order by Dispatch, which games call once at the end of each
+// frame. Events published while dispatching are delivered on the next call.
+type Bus struct {
+	mu       sync.Mutex
+	handlers map[reflect.Type][]handler
+	queue    []queued
+	nextID   uint64
+}
+
+// New creates an empty bus.
+func New() *Bus {
+	return &Bus{
+		handlers: make(map[reflect.Type][]handler),
+	}
+}
+
+// Subscription is returned by Subscribe and cancels it.
+type Subscription struct {
+	bus       *Bus
+	eventType reflect.Type
+	id        uint64
+}
+
+// Unsubscribe stops the handler from receiving events, including events that
+// are already queued. It is safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+	if s == nil || s.bus == nil {
+		return
+	}
+	b := s.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	handlers := b.handlers[s.eventType]
+	for i, h := range handlers {
+		if h.id == s.id {
+			// copy so that a dispatch in progress keeps its own slice
+			b.handlers[s.eventType] = append(handlers[:i:i], handlers[i+1:]...)
+			break
+		}
+	}
+	s.bus = nil
+}
+
+func typeOf[E any]() reflect.Type {
+	return reflect.TypeOf((*E)(nil)).Elem()
+}
+
+// Subscribe calls fn with every event of type E, in the order handlers were
+// subscribed.
+func Subscribe[E any](b *Bus, fn func(event E)) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	eventType := typeOf[E]()
+	b.handlers[eventType] = append(b.handlers[eventType], handler{
+		id: b.nextID,
+		fn: func(event interface{}) {
+			fn(event.(E))
+		},
+	})
+	return &Subscription{bus: b, eventType: eventType, id: b.nextID}
+}
+
+// Publish queues an event for the next Dispatch.
+func Publish[E any](b *Bus, event E) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue = append(b.queue, queued{eventType: typeOf[E](), event: event})
+}
+
+// Emit delivers an event to its subscribers immediately, bypassing the queue.
+func Emit[E any](b *Bus, event E) {
+	b.deliver(typeOf[E](), event)
+}
+
+// Pending returns the number of queued events.
+func (b *Bus) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue)
+}
+
+// Dispatch delivers every queued event.
+func (b *Bus) Dispatch() {
+	b.mu.Lock()
+	queue := b.queue
+	b.queue = nil
+	b.mu.Unlock()
+
+	for _, q := range queue {
+		b.deliver(q.eventType, q.event)
+	}
+}
+
+// Clear drops queued events without delivering them.
+func (b *Bus) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue = nil
+}
+
+func (b *Bus) deliver(eventType reflect.Type, event interface{}) {
+	b.mu.Lock()
+	handlers := b.handlers[eventType]
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		if b.subscribed(eventType, h.id) {
+			h.fn(event)
+		}
+	}
+}
+
+// subscribed checks that a handler was not removed by an earlier handler for
+// the same event.
+func (b *Bus) subscribed(eventType reflect.Type, id uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, h := range b.handlers[eventType] {
+		if h.id == id {
+			return true
+		}
+	}
+	return false
+}
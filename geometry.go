@@ -0,0 +1,118 @@
+package games
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// epsilon is the tolerance for treating lines as parallel.
+const epsilon = 1e-9
+
+func cross(a, b pixel.Vec) float64 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+// SegmentIntersection returns where segments a1-a2 and b1-b2 cross.
+// Parallel segments, even overlapping ones, do not intersect.
+func SegmentIntersection(a1, a2, b1, b2 pixel.Vec) (pixel.Vec, bool) {
+	t, u, ok := lineIntersection(a1, a2.Sub(a1), b1, b2.Sub(b1))
+	if !ok || t < 0 || t > 1 || u < 0 || u > 1 {
+		return pixel.ZV, false
+	}
+	return a1.Add(a2.Sub(a1).Scaled(t)), true
+}
+
+// RaySegmentIntersection returns where a ray from origin in direction dir
+// first hits the segment b1-b2, and the distance along the ray in units of
+// dir.
+func RaySegmentIntersection(origin, dir, b1, b2 pixel.Vec) (pixel.Vec, float64, bool) {
+	t, u, ok := lineIntersection(origin, dir, b1, b2.Sub(b1))
+	if !ok || t < 0 || u < 0 || u > 1 {
+		return pixel.ZV, 0, false
+	}
+	return origin.Add(dir.Scaled(t)), t, true
+}
+
+// lineIntersection solves p+t*r = q+u*s.
+func lineIntersection(p, r, q, s pixel.Vec) (t, u float64, ok bool) {
+	denominator := cross(r, s)
+	if math.Abs(denominator) < epsilon {
+		return 0, 0, false
+	}
+	qp := q.Sub(p)
+	return cross(qp, s) / denominator, cross(qp, r) / denominator, true
+}
+
+// ClosestPointOnSegment returns the point on segment a-b nearest to p.
+func ClosestPointOnSegment(p, a, b pixel.Vec) pixel.Vec {
+	ab := b.Sub(a)
+	lengthSq := ab.Dot(ab)
+	if lengthSq == 0 {
+		return a
+	}
+	t := LimitWithinBounds(p.Sub(a).Dot(ab)/lengthSq, 0, 1)
+	return a.Add(ab.Scaled(t))
+}
+
+// DistanceToSegment returns the distance from p to segment a-b.
+func DistanceToSegment(p, a, b pixel.Vec) float64 {
+	return Distance(p, ClosestPointOnSegment(p, a, b))
+}
+
+// PointInPolygon reports whether p is inside the polygon using the even-odd
+// rule. Points on an edge or vertex are inside.
+func PointInPolygon(p pixel.Vec, polygon []pixel.Vec) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if DistanceToSegment(p, a, b) < epsilon {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// SignedPolygonArea returns the area of a simple polygon, positive when its
+// points wind counter-clockwise.
+func SignedPolygonArea(polygon []pixel.Vec) float64 {
+	area := 0.0
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		area += cross(polygon[j], polygon[i])
+	}
+	return area / 2
+}
+
+func PolygonArea(polygon []pixel.Vec) float64 {
+	return math.Abs(SignedPolygonArea(polygon))
+}
+
+// PolygonCentroid returns the center of mass of a simple polygon. Degenerate
+// polygons with no area return the average of their points.
+func PolygonCentroid(polygon []pixel.Vec) pixel.Vec {
+	if len(polygon) == 0 {
+		return pixel.ZV
+	}
+	area := SignedPolygonArea(polygon)
+	if math.Abs(area) < epsilon {
+		sum := pixel.ZV
+		for _, p := range polygon {
+			sum = sum.Add(p)
+		}
+		return sum.Scaled(1 / float64(len(polygon)))
+	}
+	var cx, cy float64
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[j], polygon[i]
+		c := cross(a, b)
+		cx += (a.X + b.X) * c
+		cy += (a.Y + b.Y) * c
+	}
+	return pixel.V(cx/(6*area), cy/(6*area))
+}
@@ -0,0 +1,169 @@
+package games
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func near(a, b pixel.Vec) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestSegmentIntersection(t *testing.T) {
+	tests := []struct {
+		name           string
+		a1, a2, b1, b2 pixel.Vec
+		want           pixel.Vec
+		ok             bool
+	}{
+		{"crossing", pixel.V(0, 0), pixel.V(2, 2), pixel.V(0, 2), pixel.V(2, 0), pixel.V(1, 1), true},
+		{"touching at an end", pixel.V(0, 0), pixel.V(1, 1), pixel.V(1, 1), pixel.V(2, 0), pixel.V(1, 1), true},
+		{"t junction", pixel.V(0, 0), pixel.V(2, 0), pixel.V(1, 0), pixel.V(1, 5), pixel.V(1, 0), true},
+		{"lines cross past the ends", pixel.V(0, 0), pixel.V(1, 1), pixel.V(3, 0), pixel.V(2, 1), pixel.ZV, false},
+		{"parallel", pixel.V(0, 0), pixel.V(2, 0), pixel.V(0, 1), pixel.V(2, 1), pixel.ZV, false},
+		{"collinear overlapping", pixel.V(0, 0), pixel.V(2, 0), pixel.V(1, 0), pixel.V(3, 0), pixel.ZV, false},
+		{"collinear apart", pixel.V(0, 0), pixel.V(1, 0), pixel.V(2, 0), pixel.V(3, 0), pixel.ZV, false},
+	}
+	for _, test := range tests {
+		got, ok := SegmentIntersection(test.a1, test.a2, test.b1, test.b2)
+		if ok != test.ok || !near(got, test.want) {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRaySegmentIntersection(t *testing.T) {
+	tests := []struct {
+		name                string
+		origin, dir, b1, b2 pixel.Vec
+		want                pixel.Vec
+		distance            float64
+		ok                  bool
+	}{
+		{"ahead", pixel.V(0, 0), pixel.V(1, 0), pixel.V(3, -1), pixel.V(3, 1), pixel.V(3, 0), 3, true},
+		{"distance in units of dir", pixel.V(0, 0), pixel.V(2, 0), pixel.V(3, -1), pixel.V(3, 1), pixel.V(3, 0), 1.5, true},
+		{"through an end", pixel.V(0, 0), pixel.V(1, 1), pixel.V(2, 2), pixel.V(4, 0), pixel.V(2, 2), 2, true},
+		{"from the segment", pixel.V(3, 0), pixel.V(1, 0), pixel.V(3, -1), pixel.V(3, 1), pixel.V(3, 0), 0, true},
+		{"behind", pixel.V(0, 0), pixel.V(-1, 0), pixel.V(3, -1), pixel.V(3, 1), pixel.ZV, 0, false},
+		{"beside", pixel.V(0, 0), pixel.V(1, 0), pixel.V(3, 1), pixel.V(3, 2), pixel.ZV, 0, false},
+		{"parallel", pixel.V(0, 0), pixel.V(1, 0), pixel.V(0, 1), pixel.V(5, 1), pixel.ZV, 0, false},
+		{"collinear", pixel.V(0, 0), pixel.V(1, 0), pixel.V(2, 0), pixel.V(5, 0), pixel.ZV, 0, false},
+	}
+	for _, test := range tests {
+		got, distance, ok := RaySegmentIntersection(test.origin, test.dir, test.b1, test.b2)
+		if ok != test.ok || !near(got, test.want) || math.Abs(distance-test.distance) > 1e-9 {
+			t.Errorf("%s: got %v, %v, %v, want %v, %v, %v", test.name, got, distance, ok, test.want, test.distance, test.ok)
+		}
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	square := []pixel.Vec{pixel.V(0, 0), pixel.V(4, 0), pixel.V(4, 4), pixel.V(0, 4)}
+	// a concave U opening upwards
+	u := []pixel.Vec{pixel.V(0, 0), pixel.V(3, 0), pixel.V(3, 3), pixel.V(2, 3), pixel.V(2, 1), pixel.V(1, 1), pixel.V(1, 3), pixel.V(0, 3)}
+	tests := []struct {
+		name    string
+		polygon []pixel.Vec
+		p       pixel.Vec
+		want    bool
+	}{
+		{"inside", square, pixel.V(2, 2), true},
+		{"outside", square, pixel.V(5, 2), false},
+		{"level with an edge", square, pixel.V(5, 4), false},
+		{"bottom edge", square, pixel.V(2, 0), true},
+		{"top edge", square, pixel.V(2, 4), true},
+		{"left edge", square, pixel.V(0, 2), true},
+		{"right edge", square, pixel.V(4, 2), true},
+		{"bottom left vertex", square, pixel.V(0, 0), true},
+		{"top right vertex", square, pixel.V(4, 4), true},
+		{"in a leg of the u", u, pixel.V(0.5, 2), true},
+		{"in the gap of the u", u, pixel.V(1.5, 2), false},
+		{"inner vertex of the u", u, pixel.V(1, 1), true},
+		{"inner edge of the u", u, pixel.V(1.5, 1), true},
+	}
+	for _, test := range tests {
+		if got := PointInPolygon(test.p, test.polygon); got != test.want {
+			t.Errorf("%s: PointInPolygon(%v) = %v, want %v", test.name, test.p, got, test.want)
+		}
+	}
+}
+
+func reversed(polygon []pixel.Vec) []pixel.Vec {
+	r := make([]pixel.Vec, len(polygon))
+	for i, p := range polygon {
+		r[len(polygon)-1-i] = p
+	}
+	return r
+}
+
+func TestPolygonAreaAndCentroid(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  []pixel.Vec
+		area     float64
+		centroid pixel.Vec
+	}{
+		{"unit square", []pixel.Vec{pixel.V(0, 0), pixel.V(1, 0), pixel.V(1, 1), pixel.V(0, 1)}, 1, pixel.V(0.5, 0.5)},
+		{"moved rectangle", []pixel.Vec{pixel.V(2, 1), pixel.V(6, 1), pixel.V(6, 3), pixel.V(2, 3)}, 8, pixel.V(4, 2)},
+		{"triangle", []pixel.Vec{pixel.V(0, 0), pixel.V(3, 0), pixel.V(0, 3)}, 4.5, pixel.V(1, 1)},
+		// an L of a 2x2 square with its top right quarter missing
+		{"l shape", []pixel.Vec{pixel.V(0, 0), pixel.V(2, 0), pixel.V(2, 1), pixel.V(1, 1), pixel.V(1, 2), pixel.V(0, 2)}, 3, pixel.V(5.0/6, 5.0/6)},
+	}
+	for _, test := range tests {
+		// the tests are written counter-clockwise
+		for _, winding := range []struct {
+			name    string
+			polygon []pixel.Vec
+			sign    float64
+		}{
+			{"counter-clockwise", test.polygon, 1},
+			{"clockwise", reversed(test.polygon), -1},
+		} {
+			if got := SignedPolygonArea(winding.polygon); math.Abs(got-winding.sign*test.area) > 1e-9 {
+				t.Errorf("%s %s: SignedPolygonArea = %v, want %v", test.name, winding.name, got, winding.sign*test.area)
+			}
+			if got := PolygonArea(winding.polygon); math.Abs(got-test.area) > 1e-9 {
+				t.Errorf("%s %s: PolygonArea = %v, want %v", test.name, winding.name, got, test.area)
+			}
+			if got := PolygonCentroid(winding.polygon); !near(got, test.centroid) {
+				t.Errorf("%s %s: PolygonCentroid = %v, want %v", test.name, winding.name, got, test.centroid)
+			}
+		}
+	}
+}
+
+func TestPolygonCentroidDegenerate(t *testing.T) {
+	line := []pixel.Vec{pixel.V(0, 0), pixel.V(2, 0), pixel.V(4, 0)}
+	if got, want := PolygonCentroid(line), pixel.V(2, 0); !near(got, want) {
+		t.Errorf("PolygonCentroid of a line = %v, want %v", got, want)
+	}
+	if got := PolygonCentroid(nil); got != pixel.ZV {
+		t.Errorf("PolygonCentroid of nothing = %v, want the origin", got)
+	}
+}
+
+func TestClosestPointOnSegment(t *testing.T) {
+	tests := []struct {
+		name     string
+		p, a, b  pixel.Vec
+		want     pixel.Vec
+		distance float64
+	}{
+		{"above the middle", pixel.V(1, 1), pixel.V(0, 0), pixel.V(2, 0), pixel.V(1, 0), 1},
+		{"on the segment", pixel.V(0.5, 0), pixel.V(0, 0), pixel.V(2, 0), pixel.V(0.5, 0), 0},
+		{"past b", pixel.V(5, 4), pixel.V(0, 0), pixel.V(2, 0), pixel.V(2, 0), 5},
+		{"before a", pixel.V(-3, 0), pixel.V(0, 0), pixel.V(2, 0), pixel.V(0, 0), 3},
+		{"diagonal", pixel.V(0, 2), pixel.V(0, 0), pixel.V(2, 2), pixel.V(1, 1), math.Sqrt2},
+		{"degenerate", pixel.V(3, 4), pixel.V(0, 0), pixel.V(0, 0), pixel.V(0, 0), 5},
+	}
+	for _, test := range tests {
+		if got := ClosestPointOnSegment(test.p, test.a, test.b); !near(got, test.want) {
+			t.Errorf("%s: ClosestPointOnSegment = %v, want %v", test.name, got, test.want)
+		}
+		if got := DistanceToSegment(test.p, test.a, test.b); math.Abs(got-test.distance) > 1e-9 {
+			t.Errorf("%s: DistanceToSegment = %v, want %v", test.name, got, test.distance)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package highscores
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+)
+
+const cursorBlinkPerSecond = 2
+
+var _ games.Updater = &NamePrompt{}
+
+// NamePrompt is an on-screen text box for entering a name, fed by the
+// window's typed-text input.
+type NamePrompt struct {
+	title     string
+	maxLength int
+	name      []rune
+	done      bool
+	cancelled bool
+	blink     float64
+	txt       *text.Text
+}
+
+// NewNamePrompt creates a prompt showing title above the name being typed,
+// prefilled with name.
+func NewNamePrompt(title, name string, maxLength int, atlas *text.Atlas) *NamePrompt {
+	p := &NamePrompt{
+		title:     title,
+		maxLength: maxLength,
+		txt:       text.New(pixel.ZV, atlas),
+	}
+	p.appendText(name)
+	return p
+}
+
+// HandleInput reads typed text, backspace, enter and escape from win. Call it
+// once per frame while the prompt is shown.
+func (p *NamePrompt) HandleInput(win *pixelgl.Window) {
+	if p.done {
+		return
+	}
+	p.appendText(win.Typed())
+	if (win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace)) && len(p.name) > 0 {
+		p.name = p.name[:len(p.name)-1]
+	}
+	if win.JustPressed(pixelgl.KeyEnter) && len(p.Name()) > 0 {
+		p.done = true
+	}
+	if win.JustPressed(pixelgl.KeyEscape) {
+		p.done = true
+		p.cancelled = true
+	}
+}
+
+func (p *NamePrompt) appendText(s string) {
+	for _, r := range s {
+		if len(p.name) >= p.maxLength {
+			return
+		}
+		if unicode.IsPrint(r) {
+			p.name = append(p.name, r)
+		}
+	}
+}
+
+func (p *NamePrompt) Update(dt float64) {
+	p.blink += dt
+}
+
+// Done reports whether the name was accepted or the prompt cancelled.
+func (p *NamePrompt) Done() bool {
+	return p.done
+}
+
+// Cancelled reports whether the prompt was dismissed with escape.
+func (p *NamePrompt) Cancelled() bool {
+	return p.cancelled
+}
+
+// Name returns the name typed so far.
+func (p *NamePrompt) Name() string {
+	return strings.TrimSpace(string(p.name))
+}
+
+// Draw draws the prompt centered on center.
+func (p *NamePrompt) Draw(t pixel.Target, center pixel.Vec) {
+	cursor := " "
+	if int(p.blink*cursorBlinkPerSecond)%2 == 0 {
+		cursor = "_"
+	}
+	p.txt.Clear()
+	p.txt.Dot = p.txt.Orig
+	fmt.Fprintln(p.txt, p.title)
+	fmt.Fprintf(p.txt, "> %s%s\n", string(p.name), cursor)
+	fmt.Fprint(p.txt, "ENTER to save, ESC to skip")
+	p.txt.Draw(t, pixel.IM.Moved(center.Sub(p.txt.Bounds().Center())))
+}
+
+// WriteEntries writes a leaderboard as aligned text, marking the entry at
+// highlight, a 1-based rank, with an arrow.
+func WriteEntries(w io.Writer, entries []Entry, highlight int) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "no scores yet")
+		return
+	}
+	for i, entry := range entries {
+		marker := "  "
+		if i+1 == highlight {
+			marker = "> "
+		}
+		fmt.Fprintf(w, "%s%2d. %-12s %6d %8s\n", marker, i+1, entry.Name, entry.Score, entry.Time.Round(time.Second/10))
+	}
+}
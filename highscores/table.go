@@ -0,0 +1,180 @@
+package highscores
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/explodes/gogames/persistence"
+)
+
+// DefaultLimit is the number of entries kept per leaderboard.
+const DefaultLimit = 10
+
+// Order decides which of two scores ranks higher. Ties on score are broken by
+// the faster time, then by the earlier date.
+type Order int
+
+const (
+	// HighestFirst ranks larger scores higher, e.g. points.
+	HighestFirst Order = iota
+	// LowestFirst ranks smaller scores higher, e.g. moves.
+	LowestFirst
+	// FastestFirst ranks faster times higher, breaking ties with the
+	// smaller score.
+	FastestFirst
+)
+
+func (o Order) less(a, b Entry) bool {
+	if o == FastestFirst {
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		return a.Date.Before(b.Date)
+	}
+	if a.Score != b.Score {
+		if o == LowestFirst {
+			return a.Score < b.Score
+		}
+		return a.Score > b.Score
+	}
+	if a.Time != b.Time {
+		return a.Time < b.Time
+	}
+	return a.Date.Before(b.Date)
+}
+
+// Entry is one line of a leaderboard.
+type Entry struct {
+	Name  string        `json:"name"`
+	Score int           `json:"score"`
+	Time  time.Duration `json:"time"`
+	Date  time.Time     `json:"date"`
+}
+
+type file struct {
+	LastName string             `json:"last_name"`
+	Modes    map[string][]Entry `json:"modes"`
+}
+
+// Table holds the leaderboards of one game, one per mode, such as a board
+// size or difficulty. It is stored as a single file.
+type Table struct {
+	path  string
+	limit int
+
+	mu   sync.Mutex
+	data file
+}
+
+// Open loads the high score table of a game from its save directory.
+func Open(game string) (*Table, error) {
+	dir, err := persistence.Dir(game)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find save directory: %v", err)
+	}
+	return OpenFile(filepath.Join(dir, "highscores.json"))
+}
+
+// OpenFile loads a high score table from path. A missing file is an empty table.
+func OpenFile(path string) (*Table, error) {
+	t := &Table{
+		path:  path,
+		limit: DefaultLimit,
+		data:  file{Modes: make(map[string][]Entry)},
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read high scores: %v", err)
+	}
+	if err := json.Unmarshal(data, &t.data); err != nil {
+		return nil, fmt.Errorf("unable to decode high scores: %v", err)
+	}
+	if t.data.Modes == nil {
+		t.data.Modes = make(map[string][]Entry)
+	}
+	return t, nil
+}
+
+// SetLimit sets how many entries each leaderboard keeps.
+func (t *Table) SetLimit(limit int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit = limit
+}
+
+// LastName returns the name most recently entered, to prefill a prompt.
+func (t *Table) LastName() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.data.LastName
+}
+
+// Entries returns the leaderboard of a mode, best first.
+func (t *Table) Entries(mode string) []Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Entry(nil), t.data.Modes[mode]...)
+}
+
+// Qualifies reports whether a score would make it onto the leaderboard.
+func (t *Table) Qualifies(mode string, order Order, score int, elapsed time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := t.data.Modes[mode]
+	if len(entries) < t.limit {
+		return true
+	}
+	return order.less(Entry{Score: score, Time: elapsed, Date: time.Now()}, entries[len(entries)-1])
+}
+
+// Add records an entry and saves the table. It returns the entry's rank,
+// starting at 1, or 0 if it did not make the leaderboard.
+func (t *Table) Add(mode string, order Order, entry Entry) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	entries := append(t.data.Modes[mode], entry)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return order.less(entries[i], entries[j])
+	})
+	if len(entries) > t.limit {
+		entries = entries[:t.limit]
+	}
+	t.data.Modes[mode] = entries
+	t.data.LastName = entry.Name
+
+	rank := 0
+	for i, e := range entries {
+		if e == entry {
+			rank = i + 1
+			break
+		}
+	}
+	return rank, t.save()
+}
+
+func (t *Table) save() error {
+	data, err := json.MarshalIndent(t.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode high scores: %v", err)
+	}
+	if err := persistence.WriteFileAtomic(t.path, data, 0644); err != nil {
+		return fmt.Errorf("unable to write high scores: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+type Config struct {
+	Width     int     `json:"width" help:"window width in pixels" min:"1"`
+	Height    int     `json:"height" help:"window height in pixels" min:"1"`
+	Fps       int     `json:"fps" help:"frame rate limit" min:"1" max:"1000"`
+	Rows      int     `json:"rows" help:"number of rows of cells" min:"3"`
+	Columns   int     `json:"columns" help:"number of columns of cells" min:"3"`
+	Threshold float64 `json:"threshold" help:"chance of each cell starting alive" min:"0" max:"1"`
+	Assets    string  `json:"assets" help:"load shaders from this directory and reload them when they change"`
+	Seed      int64   `json:"seed" help:"random seed for the starting cells"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Width:     1000,
+		Height:    1000,
+		Fps:       60,
+		Rows:      500,
+		Columns:   500,
+		Threshold: 0.15,
+		Seed:      100,
+	}
+}
+
+func (c Config) Validate() error {
+	if c.Rows*c.Columns > 4000000 {
+		return fmt.Errorf("%dx%d cells is too many to simulate", c.Columns, c.Rows)
+	}
+	return nil
+}
@@ -3,57 +3,24 @@ package main
 import (
 	"runtime"
 
+	"embed"
 	"fmt"
 	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/assets"
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/rng"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"io/fs"
 	"log"
 	"math"
-	"math/rand"
+	"os"
 	"strings"
 )
 
 const (
-	title         = "Conway's Game of Life"
-	width, height = 1000, 1000
-	fps           = 60
-
-	rows, columns = 500, 500
-
-	threshold = 0.15
-
-	vertexShaderSource = `
-#version 410
-
-uniform mat4 projection;
-uniform mat4 camera;
-uniform mat4 model;
-uniform float timing;
-
-out vec4 vertexColor;
-in vec3 vert;
-
-void main() {
-    gl_Position = projection * camera * model * vec4(vert, 1);
-	vertexColor = gl_Position;
-}
-` + "\x00"
-
-	fragmentShaderSource = `
-#version 410
-
-out vec4 frag_colour;
-in vec4 vertexColor;
-
-uniform vec3 colorshift;
-
-const float offset = 1f;
-
-void main() {
-	frag_colour = vec4((offset+vertexColor.x)*(0.5f+colorshift.x*0.5f), (offset+vertexColor.y)*(0.5f+colorshift.y*0.5f), (offset+vertexColor.y+vertexColor.x)*(0.5f+colorshift.z*0.5f), 1);
-}
-` + "\x00"
+	title = "Conway's Game of Life"
 
 	low  = 0.0
 	mid  = 0.5
@@ -86,13 +53,12 @@ type cell struct {
 	x, y int
 }
 
-func (c *cell) draw(modelUniform int32) {
+func (c *cell) draw(modelUniform int32, scale mgl32.Mat4) {
 	if !c.alive {
 		return
 	}
 
 	trans := mgl32.Translate3D(float32(c.x), float32(c.y), 0.5)
-	scale := mgl32.Scale3D(float32(width)/float32(columns), float32(height)/float32(rows), 1)
 
 	model := scale.Mul4(trans)
 
@@ -104,11 +70,11 @@ func (c *cell) draw(modelUniform int32) {
 }
 
 // checkState determines the state of the cell for the next tick of the game.
-func (c *cell) checkState(cells []*cell) {
+func (c *cell) checkState(cells []*cell, rows, columns int) {
 	c.alive = c.aliveNext
 	c.aliveNext = c.alive
 
-	liveCount := c.liveNeighbors(cells)
+	liveCount := c.liveNeighbors(cells, rows, columns)
 	if c.alive {
 		// 1. Any live cell with fewer than two live neighbours dies, as if caused by underpopulation.
 		if liveCount < 2 {
@@ -133,7 +99,7 @@ func (c *cell) checkState(cells []*cell) {
 }
 
 // liveNeighbors returns the number of live neighbors for a cell.
-func (c *cell) liveNeighbors(cells []*cell) int {
+func (c *cell) liveNeighbors(cells []*cell, rows, columns int) int {
 	var liveCount int
 	add := func(x, y int) {
 		// If we're at an edge, check the other side of the board.
@@ -165,52 +131,100 @@ func (c *cell) liveNeighbors(cells []*cell) int {
 	return liveCount
 }
 
+//go:embed shaders
+var embeddedShaders embed.FS
+
+const (
+	vertexShaderName   = "cell.vert"
+	fragmentShaderName = "cell.frag"
+)
+
+type uniforms struct {
+	projection, camera, model, colorshift, timing int32
+}
+
 func main() {
+	cfg := defaultConfig()
+	if err := config.Load("life", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "invalid config")
+	}
+
 	runtime.LockOSThread()
 
-	window, err := initGlfw()
+	window, err := initGlfw(cfg)
 	if err != nil {
 		exitWith(err, "cannot init window")
 	}
 	defer glfw.Terminate()
 
-	program, err := initGl()
-	if err != nil {
-		exitWith(err, "unable to create OpenGL program")
+	if err := initGl(); err != nil {
+		exitWith(err, "unable to init OpenGL")
 	}
 
-	cells := makeCells()
-
-	projectionUniform := gl.GetUniformLocation(program, gl.Str("projection\x00"))
-	cameraUniform := gl.GetUniformLocation(program, gl.Str("camera\x00"))
-	modelUniform := gl.GetUniformLocation(program, gl.Str("model\x00"))
-	colorshiftUniform := gl.GetUniformLocation(program, gl.Str("colorshift\x00"))
-	timingUniform := gl.GetUniformLocation(program, gl.Str("timing\x00"))
+	shaders, err := newShaderManager(cfg.Assets)
+	if err != nil {
+		exitWith(err, "unable to open shaders")
+	}
+	vertexShaderSource, err := shaders.Shader(vertexShaderName)
+	if err != nil {
+		exitWith(err, "unable to load vertex shader")
+	}
+	fragmentShaderSource, err := shaders.Shader(fragmentShaderName)
+	if err != nil {
+		exitWith(err, "unable to load fragment shader")
+	}
 
-	projection := mgl32.Ortho(0, width, 0, height, 0.1, 500)
-	gl.UniformMatrix4fv(projectionUniform, 1, false, &projection[0])
+	shadersChanged := false
+	shaders.OnReload(vertexShaderName, func(value interface{}) {
+		vertexShaderSource = value.(string)
+		shadersChanged = true
+	})
+	shaders.OnReload(fragmentShaderName, func(value interface{}) {
+		fragmentShaderSource = value.(string)
+		shadersChanged = true
+	})
+
+	program, err := makeProgram(vertexShaderSource, fragmentShaderSource)
+	if err != nil {
+		exitWith(err, "unable to create OpenGL program")
+	}
 
-	camera := mgl32.LookAtV(mgl32.Vec3{0, 0, 3}, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0})
-	gl.UniformMatrix4fv(cameraUniform, 1, false, &camera[0])
+	cells := makeCells(cfg)
+	scale := mgl32.Scale3D(float32(cfg.Width)/float32(cfg.Columns), float32(cfg.Height)/float32(cfg.Rows), 1)
 
-	model := mgl32.Ident4()
-	gl.UniformMatrix4fv(modelUniform, 1, false, &model[0])
+	u := initUniforms(program, cfg)
 
-	gl.Uniform3f(colorshiftUniform, 1, 1, 1)
+	fpsLimiter := games.NewFpsLimiter(cfg.Fps)
 
-	fpsLimiter := games.NewFpsLimiter(fps)
+	last := glfw.GetTime()
 
 	for !window.ShouldClose() {
 		fpsLimiter.StartFrame()
 
+		now := glfw.GetTime()
+		shaders.Update(now - last)
+		last = now
+
+		if shadersChanged {
+			shadersChanged = false
+			// keep the old program running if the new shaders are broken
+			if reloaded, err := makeProgram(vertexShaderSource, fragmentShaderSource); err != nil {
+				log.Println(err)
+			} else {
+				gl.DeleteProgram(program)
+				program = reloaded
+				u = initUniforms(program, cfg)
+			}
+		}
+
 		for _, cell := range cells {
-			cell.checkState(cells)
+			cell.checkState(cells, cfg.Rows, cfg.Columns)
 		}
 
-		gl.Uniform1f(timingUniform, float32(glfw.GetTime()))
-		gl.Uniform3f(colorshiftUniform, float32(math.Sin(glfw.GetTime())), float32(math.Cos(glfw.GetTime())), float32(math.Sin(glfw.GetTime()))*float32(math.Cos(glfw.GetTime())))
+		gl.Uniform1f(u.timing, float32(glfw.GetTime()))
+		gl.Uniform3f(u.colorshift, float32(math.Sin(glfw.GetTime())), float32(math.Cos(glfw.GetTime())), float32(math.Sin(glfw.GetTime()))*float32(math.Cos(glfw.GetTime())))
 
-		if err := draw(cells, window, program, projectionUniform, cameraUniform, modelUniform); err != nil {
+		if err := draw(cells, window, program, u.projection, u.camera, u.model, scale); err != nil {
 			exitWith(err, "window draw failure")
 		}
 
@@ -219,20 +233,56 @@ func main() {
 	}
 }
 
-func makeCells() []*cell {
-	rand.Seed(100)
+// newShaderManager serves the embedded shaders, or the shaders in assetsDir
+// with hot reload when it is set.
+func newShaderManager(assetsDir string) (*assets.Manager, error) {
+	if assetsDir != "" {
+		return assets.NewDir(assetsDir), nil
+	}
+	fsys, err := fs.Sub(embeddedShaders, "shaders")
+	if err != nil {
+		return nil, err
+	}
+	return assets.New(fsys), nil
+}
+
+func initUniforms(program uint32, cfg Config) uniforms {
+	u := uniforms{
+		projection: gl.GetUniformLocation(program, gl.Str("projection\x00")),
+		camera:     gl.GetUniformLocation(program, gl.Str("camera\x00")),
+		model:      gl.GetUniformLocation(program, gl.Str("model\x00")),
+		colorshift: gl.GetUniformLocation(program, gl.Str("colorshift\x00")),
+		timing:     gl.GetUniformLocation(program, gl.Str("timing\x00")),
+	}
+
+	projection := mgl32.Ortho(0, float32(cfg.Width), 0, float32(cfg.Height), 0.1, 500)
+	gl.UniformMatrix4fv(u.projection, 1, false, &projection[0])
+
+	camera := mgl32.LookAtV(mgl32.Vec3{0, 0, 3}, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0})
+	gl.UniformMatrix4fv(u.camera, 1, false, &camera[0])
+
+	model := mgl32.Ident4()
+	gl.UniformMatrix4fv(u.model, 1, false, &model[0])
+
+	gl.Uniform3f(u.colorshift, 1, 1, 1)
+
+	return u
+}
+
+func makeCells(cfg Config) []*cell {
+	rng := rng.New(cfg.Seed)
 
 	drawable := makeVao(square)
 
-	cells := make([]*cell, rows*columns, rows*columns)
-	for x := 0; x < columns; x++ {
-		for y := 0; y < rows; y++ {
+	cells := make([]*cell, cfg.Rows*cfg.Columns, cfg.Rows*cfg.Columns)
+	for x := 0; x < cfg.Columns; x++ {
+		for y := 0; y < cfg.Rows; y++ {
 			c := newCell(x, y, drawable)
 
-			c.alive = rand.Float64() < threshold
+			c.alive = rng.Chance(cfg.Threshold)
 			c.aliveNext = c.alive
 
-			cells[x+y*columns] = c
+			cells[x+y*cfg.Columns] = c
 		}
 	}
 	return cells
@@ -247,7 +297,7 @@ func newCell(x, y int, drawable uint32) *cell {
 	}
 }
 
-func initGlfw() (*glfw.Window, error) {
+func initGlfw(cfg Config) (*glfw.Window, error) {
 	if err := glfw.Init(); err != nil {
 		return nil, err
 	}
@@ -257,7 +307,7 @@ func initGlfw() (*glfw.Window, error) {
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 
-	window, err := glfw.CreateWindow(width, height, title, nil, nil)
+	window, err := glfw.CreateWindow(cfg.Width, cfg.Height, title, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -266,22 +316,30 @@ func initGlfw() (*glfw.Window, error) {
 	return window, nil
 }
 
-func initGl() (uint32, error) {
+func initGl() error {
 	if err := gl.Init(); err != nil {
-		return 0, err
+		return err
 	}
 	version := gl.GoStr(gl.GetString(gl.VERSION))
 	log.Println("OpenGL version", version)
 
+	gl.ClearColor(0, 0, 0, 1)
+
+	return nil
+}
+
+func makeProgram(vertexShaderSource, fragmentShaderSource string) (uint32, error) {
 	vertexShader, err := compileShader(vertexShaderSource, gl.VERTEX_SHADER)
 	if err != nil {
 		return 0, err
 	}
+	defer gl.DeleteShader(vertexShader)
 
 	fragmentShader, err := compileShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
 	if err != nil {
 		return 0, err
 	}
+	defer gl.DeleteShader(fragmentShader)
 
 	program := gl.CreateProgram()
 	gl.AttachShader(program, vertexShader)
@@ -296,12 +354,12 @@ func initGl() (uint32, error) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
 
 		return 0, fmt.Errorf("failed to link program: %v", log)
 	}
 
 	gl.UseProgram(program)
-	gl.ClearColor(0, 0, 0, 1)
 
 	return program, nil
 }
@@ -323,12 +381,12 @@ func makeVao(points []float32) uint32 {
 
 }
 
-func draw(cells []*cell, window *glfw.Window, program uint32, projectionUniform int32, cameraUniform int32, modelUniform int32) error {
+func draw(cells []*cell, window *glfw.Window, program uint32, projectionUniform int32, cameraUniform int32, modelUniform int32, scale mgl32.Mat4) error {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.UseProgram(program)
 
 	for _, cell := range cells {
-		cell.draw(modelUniform)
+		cell.draw(modelUniform, scale)
 	}
 
 	glfw.PollEvents()
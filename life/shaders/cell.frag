@@ -0,0 +1,12 @@
+#version 410
+
+out vec4 frag_colour;
+in vec4 vertexColor;
+
+uniform vec3 colorshift;
+
+const float offset = 1f;
+
+void main() {
+	frag_colour = vec4((offset+vertexColor.x)*(0.5f+colorshift.x*0.5f), (offset+vertexColor.y)*(0.5f+colorshift.y*0.5f), (offset+vertexColor.y+vertexColor.x)*(0.5f+colorshift.z*0.5f), 1);
+}
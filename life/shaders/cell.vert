@@ -0,0 +1,14 @@
+#version 410
+
+uniform mat4 projection;
+uniform mat4 camera;
+uniform mat4 model;
+uniform float timing;
+
+out vec4 vertexColor;
+in vec3 vert;
+
+void main() {
+    gl_Position = projection * camera * model * vec4(vert, 1);
+	vertexColor = gl_Position;
+}
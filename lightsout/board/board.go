@@ -0,0 +1,336 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board is a Lights Out grid. Pressing a light toggles it and its
+// neighbours, as decided by the board's Rules, and the puzzle is solved when
+// every light is off. (0, 0) is the bottom left light.
+type Board struct {
+	width, height int
+	rules         Rules
+	// states holds each light's state, 0 for off.
+	states []uint8
+	moves  int
+}
+
+// New creates a classic board with every light off.
+func New(width, height int) *Board {
+	return &Board{
+		width:  width,
+		height: height,
+		rules:  Classic,
+		states: make([]uint8, width*height),
+	}
+}
+
+// NewWithRules creates a board with every light off.
+func NewWithRules(width, height int, rules Rules) (*Board, error) {
+	if err := rules.Validate(width, height); err != nil {
+		return nil, err
+	}
+	b := New(width, height)
+	b.rules = rules
+	return b, nil
+}
+
+// NewLit creates a classic board with every light on.
+func NewLit(width, height int) *Board {
+	b := New(width, height)
+	b.SetAll(true)
+	return b
+}
+
+// FromLights creates a board from lights in row order, starting at the
+// bottom row.
+func FromLights(width, height int, lights []bool) (*Board, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid board size %dx%d", width, height)
+	}
+	if len(lights) != width*height {
+		return nil, fmt.Errorf("board has %d lights, expected %d", len(lights), width*height)
+	}
+	b := New(width, height)
+	for i, on := range lights {
+		if on {
+			b.states[i] = 1
+		}
+	}
+	return b, nil
+}
+
+// FromStates creates a board from light states in row order, starting at the
+// bottom row.
+func FromStates(width, height int, rules Rules, states []int) (*Board, error) {
+	b, err := NewWithRules(width, height, rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) != width*height {
+		return nil, fmt.Errorf("board has %d lights, expected %d", len(states), width*height)
+	}
+	for i, state := range states {
+		if state < 0 || state >= rules.States {
+			return nil, fmt.Errorf("light %d has state %d, expected 0 to %d", i, state, rules.States-1)
+		}
+		b.states[i] = uint8(state)
+	}
+	return b, nil
+}
+
+func (b *Board) Width() int {
+	return b.width
+}
+
+func (b *Board) Height() int {
+	return b.height
+}
+
+func (b *Board) Rules() Rules {
+	return b.rules
+}
+
+// Moves returns the number of presses since the board was created or reset.
+func (b *Board) Moves() int {
+	return b.moves
+}
+
+// SetMoves sets the move counter, such as when restoring a saved game.
+func (b *Board) SetMoves(moves int) {
+	b.moves = moves
+}
+
+// Contains reports whether (x, y) is on the board.
+func (b *Board) Contains(x, y int) bool {
+	return x >= 0 && x < b.width && y >= 0 && y < b.height
+}
+
+// Index returns the position of (x, y) in Lights.
+func (b *Board) Index(x, y int) int {
+	return x + y*b.width
+}
+
+// Light reports whether the light at (x, y) is on, in any state but 0.
+// Lights off the board are always off.
+func (b *Board) Light(x, y int) bool {
+	return b.State(x, y) != 0
+}
+
+// State returns the state of the light at (x, y), 0 when it is off.
+func (b *Board) State(x, y int) int {
+	if !b.Contains(x, y) {
+		return 0
+	}
+	return int(b.states[b.Index(x, y)])
+}
+
+// Set turns the light at (x, y) on, in state 1, or off without counting a
+// move.
+func (b *Board) Set(x, y int, on bool) {
+	if on {
+		b.SetState(x, y, 1)
+	} else {
+		b.SetState(x, y, 0)
+	}
+}
+
+// SetState changes the state of the light at (x, y) without counting a move.
+func (b *Board) SetState(x, y, state int) {
+	if b.Contains(x, y) {
+		b.states[b.Index(x, y)] = uint8(((state % b.rules.States) + b.rules.States) % b.rules.States)
+	}
+}
+
+// SetAll turns every light on, in state 1, or off without counting a move.
+func (b *Board) SetAll(on bool) {
+	var state uint8
+	if on {
+		state = 1
+	}
+	for i := range b.states {
+		b.states[i] = state
+	}
+}
+
+// Lights returns which lights are on in row order, starting at the bottom
+// row.
+func (b *Board) Lights() []bool {
+	lights := make([]bool, len(b.states))
+	for i, state := range b.states {
+		lights[i] = state != 0
+	}
+	return lights
+}
+
+// States returns the state of every light in row order, starting at the
+// bottom row.
+func (b *Board) States() []int {
+	states := make([]int, len(b.states))
+	for i, state := range b.states {
+		states[i] = int(state)
+	}
+	return states
+}
+
+// Lit returns the number of lights that are on.
+func (b *Board) Lit() int {
+	lit := 0
+	for _, state := range b.states {
+		if state != 0 {
+			lit++
+		}
+	}
+	return lit
+}
+
+// Toggle advances the light at (x, y) and its neighbours to their next state
+// without counting a move, for setting up puzzles.
+func (b *Board) Toggle(x, y int) {
+	for _, o := range b.rules.Neighbourhood {
+		b.toggle(b.rules.neighbour(b.width, b.height, x, y, o))
+	}
+}
+
+func (b *Board) toggle(x, y int) {
+	if b.Contains(x, y) {
+		i := b.Index(x, y)
+		b.states[i] = (b.states[i] + 1) % uint8(b.rules.States)
+	}
+}
+
+// Toggled returns the cells a press at (x, y) advances, once per time they
+// are advanced.
+func (b *Board) Toggled(x, y int) [][2]int {
+	var cells [][2]int
+	for _, o := range b.rules.Neighbourhood {
+		nx, ny := b.rules.neighbour(b.width, b.height, x, y, o)
+		if b.Contains(nx, ny) {
+			cells = append(cells, [2]int{nx, ny})
+		}
+	}
+	return cells
+}
+
+// Press advances the light at (x, y) and its neighbours and counts a move. It
+// returns false, doing nothing, when (x, y) is off the board.
+func (b *Board) Press(x, y int) bool {
+	if !b.Contains(x, y) {
+		return false
+	}
+	b.Toggle(x, y)
+	b.moves++
+	return true
+}
+
+// Unpress takes back a press at (x, y), uncounting the move. Lights with k
+// states are moved back one state, as pressing k-1 more times would.
+func (b *Board) Unpress(x, y int) bool {
+	if !b.Contains(x, y) {
+		return false
+	}
+	for i := 1; i < b.rules.States; i++ {
+		b.Toggle(x, y)
+	}
+	b.moves--
+	return true
+}
+
+// IsSolved reports whether every light is off.
+func (b *Board) IsSolved() bool {
+	for _, state := range b.states {
+		if state != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns an independent copy of the board, including its move count.
+func (b *Board) Clone() *Board {
+	return &Board{
+		width:  b.width,
+		height: b.height,
+		rules:  b.rules,
+		states: append([]uint8(nil), b.states...),
+		moves:  b.moves,
+	}
+}
+
+// Equal reports whether two boards have the same size, rules and lights,
+// ignoring moves.
+func (b *Board) Equal(other *Board) bool {
+	if b.width != other.width || b.height != other.height || b.rules.String() != other.rules.String() {
+		return false
+	}
+	for i, state := range b.states {
+		if other.states[i] != state {
+			return false
+		}
+	}
+	return true
+}
+
+// String draws the board top row first, as it appears on screen, with . for
+// lights that are off. Lights that are on are # on two state boards and
+// their state number otherwise.
+func (b *Board) String() string {
+	var sb strings.Builder
+	for y := b.height - 1; y >= 0; y-- {
+		for x := 0; x < b.width; x++ {
+			switch state := b.State(x, y); {
+			case state == 0:
+				sb.WriteByte('.')
+			case b.rules.States == 2:
+				sb.WriteByte('#')
+			default:
+				sb.WriteByte(byte('0' + state))
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Parse reads a classic board drawn by String. Blank lines are ignored and
+// every row must have the same width.
+func Parse(s string) (*Board, error) {
+	return ParseWithRules(s, Classic)
+}
+
+// ParseWithRules reads a board drawn by String.
+func ParseWithRules(s string, rules Rules) (*Board, error) {
+	var rows []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty board")
+	}
+	width, height := len(rows[0]), len(rows)
+	b, err := NewWithRules(width, height, rules)
+	if err != nil {
+		return nil, err
+	}
+	for r, row := range rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("row %d has %d lights, expected %d", r+1, len(row), width)
+		}
+		y := height - 1 - r
+		for x, c := range row {
+			switch {
+			case c == '#':
+				b.Set(x, y, true)
+			case c == '.':
+			case c > '0' && int(c-'0') < rules.States:
+				b.SetState(x, y, int(c-'0'))
+			default:
+				return nil, fmt.Errorf("row %d: unexpected %q", r+1, c)
+			}
+		}
+	}
+	return b, nil
+}
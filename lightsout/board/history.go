@@ -0,0 +1,103 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Move is one press.
+type Move struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// String names the move like a chess square: a column letter, from a at the
+// left, and a row number, from 1 at the bottom.
+func (m Move) String() string {
+	return columnName(m.X) + strconv.Itoa(m.Y+1)
+}
+
+func columnName(x int) string {
+	name := ""
+	for x++; x > 0; x = (x - 1) / 26 {
+		name = string(rune('a'+(x-1)%26)) + name
+	}
+	return name
+}
+
+// History records presses so they can be undone and redone. Moves after
+// the cursor have been undone and are forgotten by the next press.
+type History struct {
+	moves  []Move
+	cursor int
+}
+
+// NewHistory creates a history of moves, all applied.
+func NewHistory(moves []Move) *History {
+	return &History{moves: append([]Move(nil), moves...), cursor: len(moves)}
+}
+
+// Press presses (x, y) on b and records it.
+func (h *History) Press(b *Board, x, y int) bool {
+	if !b.Press(x, y) {
+		return false
+	}
+	h.moves = append(h.moves[:h.cursor], Move{x, y})
+	h.cursor++
+	return true
+}
+
+// Undo takes back the last applied move on b.
+func (h *History) Undo(b *Board) (Move, bool) {
+	if h.cursor == 0 {
+		return Move{}, false
+	}
+	h.cursor--
+	m := h.moves[h.cursor]
+	b.Unpress(m.X, m.Y)
+	return m, true
+}
+
+// Redo applies the last undone move to b again.
+func (h *History) Redo(b *Board) (Move, bool) {
+	if h.cursor == len(h.moves) {
+		return Move{}, false
+	}
+	m := h.moves[h.cursor]
+	h.cursor++
+	b.Press(m.X, m.Y)
+	return m, true
+}
+
+func (h *History) CanUndo() bool {
+	return h.cursor > 0
+}
+
+func (h *History) CanRedo() bool {
+	return h.cursor < len(h.moves)
+}
+
+// Cursor returns the number of applied moves.
+func (h *History) Cursor() int {
+	return h.cursor
+}
+
+// Moves returns every move, applied or undone.
+func (h *History) Moves() []Move {
+	return append([]Move(nil), h.moves...)
+}
+
+// Applied returns the moves that haven't been undone.
+func (h *History) Applied() []Move {
+	return append([]Move(nil), h.moves[:h.cursor]...)
+}
+
+// SetCursor marks only the first cursor moves as applied, without touching
+// any board, such as when restoring a saved game.
+func (h *History) SetCursor(cursor int) error {
+	if cursor < 0 || cursor > len(h.moves) {
+		return fmt.Errorf("cursor %d is outside of %d moves", cursor, len(h.moves))
+	}
+	h.cursor = cursor
+	return nil
+}
@@ -0,0 +1,191 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxStates is the most states a light can cycle through.
+const MaxStates = 9
+
+// Offset is a cell relative to the one pressed. On hex grids it is in axial
+// coordinates: Q along the row and R up and to the right.
+type Offset struct {
+	DX, DY int
+}
+
+// Rules decide which lights a press changes and how.
+type Rules struct {
+	// Name is the neighbourhood's name, or a mask written as rows from the
+	// top joined by commas, such as mask:.#.,###,.#.
+	Name string
+	// Neighbourhood holds the cells a press advances, including the pressed
+	// cell itself unless the neighbourhood leaves it out.
+	Neighbourhood []Offset
+	// Wrap joins opposite edges, making the board a torus.
+	Wrap bool
+	// States is how many states each light cycles through on being toggled,
+	// 2 for on and off. Every light must be back at 0 to win.
+	States int
+	// Hex lays cells out as pointy topped hexagons with odd rows shifted
+	// right by half a cell.
+	Hex bool
+}
+
+var (
+	// Classic toggles the pressed light and the four next to it.
+	Classic = Rules{
+		Name:          "classic",
+		Neighbourhood: []Offset{{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {0, 1}},
+		States:        2,
+	}
+	// Cross toggles the pressed light and the four diagonal to it.
+	Cross = Rules{
+		Name:          "x",
+		Neighbourhood: []Offset{{0, 0}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}},
+		States:        2,
+	}
+	// Square toggles the 3×3 block around the pressed light.
+	Square = Rules{
+		Name: "square",
+		Neighbourhood: []Offset{
+			{-1, 1}, {0, 1}, {1, 1},
+			{-1, 0}, {0, 0}, {1, 0},
+			{-1, -1}, {0, -1}, {1, -1},
+		},
+		States: 2,
+	}
+	// Hex toggles the pressed hexagon and the six around it.
+	Hex = Rules{
+		Name:          "hex",
+		Neighbourhood: []Offset{{0, 0}, {1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, -1}, {-1, 1}},
+		States:        2,
+		Hex:           true,
+	}
+)
+
+var namedRules = map[string]Rules{
+	Classic.Name: Classic,
+	Cross.Name:   Cross,
+	Square.Name:  Square,
+	Hex.Name:     Hex,
+}
+
+// Mask makes rules from a picture of the neighbourhood: rows from the top
+// with # for lights a press toggles and . for lights it leaves alone. The
+// pressed light is in the middle, so the picture must have an odd width and
+// height.
+func Mask(rows []string) (Rules, error) {
+	height := len(rows)
+	if height == 0 || height%2 == 0 {
+		return Rules{}, fmt.Errorf("mask needs an odd number of rows, has %d", height)
+	}
+	width := len(rows[0])
+	if width%2 == 0 {
+		return Rules{}, fmt.Errorf("mask needs an odd number of columns, has %d", width)
+	}
+	rules := Rules{
+		Name:   "mask:" + strings.Join(rows, ","),
+		States: 2,
+	}
+	for r, row := range rows {
+		if len(row) != width {
+			return Rules{}, fmt.Errorf("mask row %d has %d cells, expected %d", r+1, len(row), width)
+		}
+		for c, cell := range row {
+			switch cell {
+			case '#':
+				rules.Neighbourhood = append(rules.Neighbourhood, Offset{c - width/2, height/2 - r})
+			case '.':
+			default:
+				return Rules{}, fmt.Errorf("mask row %d: unexpected %q", r+1, cell)
+			}
+		}
+	}
+	if len(rules.Neighbourhood) == 0 {
+		return Rules{}, fmt.Errorf("mask toggles nothing")
+	}
+	return rules, nil
+}
+
+// ParseRules reads rules written by String: a neighbourhood name or mask,
+// followed by +torus to wrap the edges and +k3 for lights with three states.
+func ParseRules(s string) (Rules, error) {
+	parts := strings.Split(s, "+")
+	var rules Rules
+	if mask := strings.TrimPrefix(parts[0], "mask:"); mask != parts[0] {
+		var err error
+		if rules, err = Mask(strings.Split(mask, ",")); err != nil {
+			return Rules{}, err
+		}
+	} else if named, ok := namedRules[parts[0]]; ok {
+		rules = named
+	} else {
+		return Rules{}, fmt.Errorf("unknown rules %q", parts[0])
+	}
+	for _, modifier := range parts[1:] {
+		switch {
+		case modifier == "torus":
+			rules.Wrap = true
+		case strings.HasPrefix(modifier, "k"):
+			states, err := strconv.Atoi(modifier[1:])
+			if err != nil || states < 2 || states > MaxStates {
+				return Rules{}, fmt.Errorf("lights need 2 to %d states, not %q", MaxStates, modifier[1:])
+			}
+			rules.States = states
+		default:
+			return Rules{}, fmt.Errorf("unknown rules modifier %q", modifier)
+		}
+	}
+	return rules, nil
+}
+
+func (r Rules) String() string {
+	s := r.Name
+	if r.Wrap {
+		s += "+torus"
+	}
+	if r.States != 2 {
+		s += fmt.Sprintf("+k%d", r.States)
+	}
+	return s
+}
+
+// Validate checks that the rules work on a width by height board.
+func (r Rules) Validate(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid board size %dx%d", width, height)
+	}
+	if r.States < 2 || r.States > MaxStates {
+		return fmt.Errorf("lights need 2 to %d states, not %d", MaxStates, r.States)
+	}
+	if len(r.Neighbourhood) == 0 {
+		return fmt.Errorf("rules %s toggle nothing", r)
+	}
+	if r.Hex && r.Wrap && height%2 != 0 {
+		// odd rows are shifted, so the top and bottom rows only line up
+		// when there is an even number of them
+		return fmt.Errorf("wrapped hex boards need an even height, not %d", height)
+	}
+	return nil
+}
+
+// neighbour returns the cell offset o from (x, y), wrapping if the rules
+// say so.
+func (r Rules) neighbour(width, height, x, y int, o Offset) (int, int) {
+	if r.Hex {
+		// odd-r offset coordinates to axial and back
+		q := x - (y-y&1)/2 + o.DX
+		y += o.DY
+		x = q + (y-y&1)/2
+	} else {
+		x += o.DX
+		y += o.DY
+	}
+	if r.Wrap {
+		x = (x%width + width) % width
+		y = (y%height + height) % height
+	}
+	return x, y
+}
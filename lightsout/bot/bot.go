@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// closeTimeout is how long a bot has to exit once its stdin is closed.
+const closeTimeout = 2 * time.Second
+
+// ErrTimeout is returned when a bot doesn't reply in time.
+var ErrTimeout = errors.New("bot did not reply in time")
+
+type reply struct {
+	Reply
+	err error
+}
+
+// Bot is the engine's end of a conversation with a bot.
+type Bot struct {
+	encoder *json.Encoder
+	replies chan reply
+	// pending is a reply that timed out, which arrives before any other.
+	pending bool
+
+	cmd   *exec.Cmd
+	stdin io.Closer
+}
+
+// New talks to a bot that reads messages from w and writes replies to r.
+func New(r io.Reader, w io.Writer) *Bot {
+	b := &Bot{
+		encoder: json.NewEncoder(w),
+		replies: make(chan reply),
+	}
+	go b.read(r)
+	return b
+}
+
+// Start runs a bot as a process, talking to it over its stdin and stdout.
+// Whatever it writes to stderr is passed on to ours.
+func Start(name string, args ...string) (*Bot, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bot stdin: %v", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bot stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start bot: %v", err)
+	}
+	b := New(stdout, stdin)
+	b.cmd, b.stdin = cmd, stdin
+	return b, nil
+}
+
+func (b *Bot) read(r io.Reader) {
+	defer close(b.replies)
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		var rep reply
+		if err := json.Unmarshal(scanner.Bytes(), &rep.Reply); err != nil {
+			rep.err = fmt.Errorf("unable to decode reply %q: %v", scanner.Text(), err)
+		}
+		b.replies <- rep
+	}
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	b.replies <- reply{err: fmt.Errorf("bot stopped replying: %v", err)}
+}
+
+// Send sends a message to the bot.
+func (b *Bot) Send(m Message) error {
+	if err := b.encoder.Encode(m); err != nil {
+		return fmt.Errorf("unable to send %s message: %v", m.Type, err)
+	}
+	return nil
+}
+
+// Receive waits for the bot's next reply, giving up after timeout when it is
+// positive. A bot that timed out is out of step and can't be used again.
+func (b *Bot) Receive(timeout time.Duration) (Reply, error) {
+	if b.pending {
+		return Reply{}, ErrTimeout
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case rep, ok := <-b.replies:
+		if !ok {
+			return Reply{}, errors.New("bot stopped replying")
+		}
+		return rep.Reply, rep.err
+	case <-expired:
+		b.pending = true
+		return Reply{}, ErrTimeout
+	}
+}
+
+// Close ends the conversation. A bot process is asked to exit by closing its
+// stdin and killed when it doesn't.
+func (b *Bot) Close() error {
+	if b.cmd == nil {
+		return nil
+	}
+	b.stdin.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- b.cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(closeTimeout):
+		b.cmd.Process.Kill()
+		return <-done
+	}
+}
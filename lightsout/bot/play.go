@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/explodes/gogames/lightsout/puzzle"
+)
+
+// Limits bound how a bot may play a puzzle.
+type Limits struct {
+	// MaxMoves is the number of presses allowed, 0 for a few times the
+	// shortest solution.
+	MaxMoves int
+	// MoveTimeout is how long the bot has to reply to each board, 0 for no
+	// limit.
+	MoveTimeout time.Duration
+}
+
+// maxMoves returns the number of presses allowed on p.
+func (l Limits) maxMoves(p *puzzle.Puzzle) int {
+	if l.MaxMoves > 0 {
+		return l.MaxMoves
+	}
+	return 4*p.Presses() + p.Board.Width()*p.Board.Height()
+}
+
+// Result is how a bot did on a puzzle.
+type Result struct {
+	Puzzle int
+	Code   string
+	Solved bool
+	// Moves is the number of presses the bot made and Best the length of the
+	// shortest solution known.
+	Moves, Best int
+	// Optimal is true when no solution is shorter than Best. The solver gives
+	// up proving it on some large boards.
+	Optimal bool
+	// Turns is the number of replies and Think the time spent waiting for
+	// them.
+	Turns int
+	Think time.Duration
+	// Reason says why the puzzle wasn't solved.
+	Reason string
+}
+
+// Play has the bot play p, the n'th puzzle of the session. Breaking the rules
+// loses the puzzle, while an error means the bot can't play any more.
+func (b *Bot) Play(n int, p *puzzle.Puzzle, limits Limits) (Result, error) {
+	board := p.Board.Clone()
+	board.SetMoves(0)
+	result := Result{
+		Puzzle:  n,
+		Code:    p.Code(),
+		Best:    p.Presses(),
+		Optimal: p.Solution.Optimal,
+	}
+	maxMoves := limits.maxMoves(p)
+
+	for !board.IsSolved() && result.Reason == "" {
+		err := b.Send(Message{
+			Type:     TypeBoard,
+			Puzzle:   n,
+			Code:     result.Code,
+			Rules:    board.Rules().String(),
+			Width:    board.Width(),
+			Height:   board.Height(),
+			States:   board.States(),
+			Moves:    board.Moves(),
+			MaxMoves: maxMoves,
+		})
+		if err != nil {
+			return result, err
+		}
+		start := time.Now()
+		reply, err := b.Receive(limits.MoveTimeout)
+		result.Think += time.Since(start)
+		if err != nil {
+			return result, err
+		}
+		result.Turns++
+
+		if len(reply.Presses) == 0 {
+			result.Reason = "gave up"
+		}
+		for _, press := range reply.Presses {
+			switch {
+			case board.Moves() >= maxMoves:
+				result.Reason = fmt.Sprintf("made more than %d presses", maxMoves)
+			case !board.Contains(press.X, press.Y):
+				result.Reason = fmt.Sprintf("pressed (%d, %d) off the %dx%d board", press.X, press.Y, board.Width(), board.Height())
+			default:
+				board.Press(press.X, press.Y)
+			}
+			if board.IsSolved() || result.Reason != "" {
+				break
+			}
+		}
+	}
+
+	result.Moves = board.Moves()
+	result.Solved = board.IsSolved()
+	err := b.Send(Message{
+		Type:    TypeResult,
+		Puzzle:  n,
+		Code:    result.Code,
+		Moves:   result.Moves,
+		Solved:  result.Solved,
+		Best:    result.Best,
+		Optimal: result.Optimal,
+		Reason:  result.Reason,
+	})
+	return result, err
+}
+
+// Hello starts a session.
+func (b *Bot) Hello() error {
+	return b.Send(Message{Type: TypeHello, Version: Version})
+}
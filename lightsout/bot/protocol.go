@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/explodes/gogames/lightsout/board"
+)
+
+// Version is the protocol version sent in the hello message.
+const Version = 1
+
+// maxLine bounds a single message, enough for a 32x32 board many times over.
+const maxLine = 1 << 20
+
+// Message types sent by the engine.
+const (
+	TypeHello  = "hello"
+	TypeBoard  = "board"
+	TypeResult = "result"
+)
+
+// Message is one line of JSON sent by the engine to a bot on its stdin.
+//
+// A session starts with a hello. Each puzzle then alternates board messages
+// with replies from the bot until the board is solved, the bot gives up or
+// breaks a limit, and ends with a result the bot doesn't reply to. The bot
+// should exit when its stdin is closed.
+type Message struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	// Puzzle counts the puzzles of the session from 1.
+	Puzzle int    `json:"puzzle,omitempty"`
+	Code   string `json:"code,omitempty"`
+	// Rules are written as in the variant option, such as classic or
+	// hex+torus+k3.
+	Rules  string `json:"rules,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	// States holds every light in row order starting at the bottom row, so
+	// the light at (x, y) is States[x+y*Width]. 0 is off.
+	States []int `json:"states,omitempty"`
+	// Moves is the number of presses made so far on the puzzle.
+	Moves int `json:"moves"`
+	// MaxMoves is the number of presses allowed on the puzzle.
+	MaxMoves int `json:"max_moves,omitempty"`
+
+	// Solved, Best, Optimal and Reason are set on results. Best is the
+	// length of the shortest solution known, Optimal is true when none is
+	// shorter and Reason says why the puzzle wasn't solved.
+	Solved  bool   `json:"solved,omitempty"`
+	Best    int    `json:"best,omitempty"`
+	Optimal bool   `json:"optimal,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Board rebuilds the board of a board message.
+func (m Message) Board() (*board.Board, error) {
+	rules, err := board.ParseRules(m.Rules)
+	if err != nil {
+		return nil, err
+	}
+	b, err := board.FromStates(m.Width, m.Height, rules, m.States)
+	if err != nil {
+		return nil, err
+	}
+	b.SetMoves(m.Moves)
+	return b, nil
+}
+
+// Press is a press of the light at (X, Y), where (0, 0) is the bottom left.
+type Press struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Reply is one line of JSON sent by a bot on its stdout after each board
+// message, such as {"presses":[{"x":1,"y":2}]}. The presses are made in
+// order, and any left once the board is solved are ignored. No presses
+// gives up the puzzle.
+type Reply struct {
+	Presses []Press `json:"presses"`
+}
+
+// newScanner reads messages one line at a time.
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLine)
+	return scanner
+}
+
+// Serve runs a bot written in Go, reading messages from r and writing the
+// presses play returns for each board to w until r is closed.
+func Serve(r io.Reader, w io.Writer, play func(b *board.Board) []Press) error {
+	scanner := newScanner(r)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		var m Message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return fmt.Errorf("unable to decode message: %v", err)
+		}
+		if m.Type != TypeBoard {
+			continue
+		}
+		b, err := m.Board()
+		if err != nil {
+			return fmt.Errorf("unable to read board of puzzle %d: %v", m.Puzzle, err)
+		}
+		presses := play(b)
+		if presses == nil {
+			presses = []Press{}
+		}
+		if err := encoder.Encode(Reply{Presses: presses}); err != nil {
+			return fmt.Errorf("unable to send reply: %v", err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/lightsout/solver"
+)
+
+type Config struct {
+	Bot            string `json:"bot" help:"command running the bot, split on spaces, such as ./solverbot -fast"`
+	Puzzles        int    `json:"puzzles" help:"number of puzzles to play" min:"1"`
+	Seed           int64  `json:"seed" help:"random seed for the puzzles, so bots can be compared on the same ones"`
+	GridSideLength int    `json:"grid_side_length" help:"number of squares along each side of the grid" min:"2" max:"32"`
+	Variant        string `json:"variant" help:"rules: classic, x, square, hex or a mask such as mask:.#.,###,.#.; add +torus to wrap the edges and +k3 for lights with 3 states"`
+	Difficulty     string `json:"difficulty" help:"puzzle difficulty: easy, medium, hard or expert"`
+	MaxMoves       int    `json:"max_moves" help:"presses allowed per puzzle, 0 for a few times the shortest solution" min:"0"`
+	MoveTimeout    int    `json:"move_timeout" help:"milliseconds the bot has to reply to each board, 0 for no limit" min:"0"`
+	Verbose        bool   `json:"verbose" help:"print the result of every puzzle"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Puzzles:        100,
+		Seed:           1,
+		GridSideLength: 8,
+		Variant:        "classic",
+		Difficulty:     "medium",
+		MoveTimeout:    5000,
+	}
+}
+
+func (c Config) Validate() error {
+	if len(strings.Fields(c.Bot)) == 0 {
+		return errors.New("no bot given, set -bot to the command running it")
+	}
+	if _, err := puzzle.ParseDifficulty(c.Difficulty); err != nil {
+		return err
+	}
+	rules, err := board.ParseRules(c.Variant)
+	if err != nil {
+		return err
+	}
+	if err := rules.Validate(c.GridSideLength, c.GridSideLength); err != nil {
+		return fmt.Errorf("variant %s: %v", c.Variant, err)
+	}
+	// puzzles are generated and rated with the solver
+	return solver.Supports(rules)
+}
@@ -0,0 +1,142 @@
+// Command runner plays seeded Lights Out puzzles against a bot speaking the
+// bot protocol and reports how it did.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/bot"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/rng"
+)
+
+// summary totals the results of a run.
+type summary struct {
+	puzzles, solved int
+	// proven counts the solved puzzles whose shortest solution is known, and
+	// moves and optimal are totals over them
+	proven         int
+	moves, optimal int
+	turns          int
+	think, slowest time.Duration
+}
+
+func (s *summary) add(r bot.Result) {
+	s.puzzles++
+	s.turns += r.Turns
+	s.think += r.Think
+	if r.Think > s.slowest {
+		s.slowest = r.Think
+	}
+	if r.Solved {
+		s.solved++
+	}
+	if r.Solved && r.Optimal {
+		s.proven++
+		s.moves += r.Moves
+		s.optimal += r.Best
+	}
+}
+
+func (s *summary) write(w io.Writer, wall time.Duration) {
+	fmt.Fprintf(w, "solved   %d/%d (%.1f%%)\n", s.solved, s.puzzles, percent(s.solved, s.puzzles))
+	if s.proven > 0 {
+		fmt.Fprintf(w, "moves    %.2f average vs %.2f optimal (%.1f%%) on %d solved puzzles with a known optimum\n",
+			float64(s.moves)/float64(s.proven), float64(s.optimal)/float64(s.proven), percent(s.moves, s.optimal), s.proven)
+	}
+	if s.turns > 0 {
+		fmt.Fprintf(w, "thinking %v per reply, %v per puzzle, %v on the slowest puzzle\n",
+			s.think/time.Duration(s.turns), s.think/time.Duration(s.puzzles), s.slowest)
+	}
+	fmt.Fprintf(w, "time     %v thinking, %v in all\n", s.think, wall)
+}
+
+func percent(n, of int) float64 {
+	if of == 0 {
+		return 100
+	}
+	return 100 * float64(n) / float64(of)
+}
+
+func main() {
+	cfg := defaultConfig()
+	if err := config.Load("lightsout-bot-runner", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "unable to load config")
+	}
+
+	size := cfg.GridSideLength
+	rules, _ := board.ParseRules(cfg.Variant)
+	difficulty, _ := puzzle.ParseDifficulty(cfg.Difficulty)
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	generator, err := puzzle.NewGenerator(size, size, rules)
+	if err != nil {
+		exitWith(err, "unable to create puzzles")
+	}
+	seeds := rng.New(seed)
+	limits := bot.Limits{
+		MaxMoves:    cfg.MaxMoves,
+		MoveTimeout: time.Duration(cfg.MoveTimeout) * time.Millisecond,
+	}
+
+	command := strings.Fields(cfg.Bot)
+	b, err := bot.Start(command[0], command[1:]...)
+	if err != nil {
+		exitWith(err, "unable to run bot %s", cfg.Bot)
+	}
+	fmt.Printf("%s: %d %dx%d %s %s puzzles, seed %d\n", cfg.Bot, cfg.Puzzles, size, size, rules, difficulty, seed)
+
+	start := time.Now()
+	var total summary
+	err = b.Hello()
+	for n := 1; n <= cfg.Puzzles && err == nil; n++ {
+		p, genErr := generator.Generate(seeds.Int63(), difficulty)
+		if genErr != nil {
+			b.Close()
+			exitWith(genErr, "unable to create puzzle %d", n)
+		}
+		var result bot.Result
+		result, err = b.Play(n, p, limits)
+		if err != nil {
+			result.Reason = err.Error()
+		}
+		total.add(result)
+		if cfg.Verbose || err != nil {
+			writeResult(os.Stdout, result)
+		}
+	}
+	if closeErr := b.Close(); closeErr != nil && err == nil {
+		fmt.Printf("bot exited badly: %v\n", closeErr)
+	}
+
+	total.write(os.Stdout, time.Since(start))
+	if err != nil {
+		fmt.Printf("bot failed after %d of %d puzzles: %v\n", total.puzzles, cfg.Puzzles, err)
+		os.Exit(1)
+	}
+}
+
+func writeResult(w io.Writer, r bot.Result) {
+	outcome := "solved"
+	if !r.Solved {
+		outcome = "failed: " + r.Reason
+	}
+	best := "optimal"
+	if !r.Optimal {
+		best = "best   "
+	}
+	fmt.Fprintf(w, "%4d %-28s %3d moves, %s %3d, %4d replies in %-12v %s\n", r.Puzzle, r.Code, r.Moves, best, r.Best, r.Turns, r.Think, outcome)
+}
+
+func exitWith(err error, msg string, args ...interface{}) {
+	fmt.Printf("%s: %v\n", fmt.Sprintf(msg, args...), err)
+	os.Exit(2)
+}
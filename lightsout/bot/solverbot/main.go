@@ -0,0 +1,36 @@
+// Command solverbot is a bot for the Lights Out bot protocol that plays the
+// solver's shortest solution, for trying out the runner and comparing other
+// bots against.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/bot"
+	"github.com/explodes/gogames/lightsout/solver"
+)
+
+func main() {
+	err := bot.Serve(os.Stdin, os.Stdout, func(b *board.Board) []bot.Press {
+		solution, err := solver.Solve(b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "solverbot: %v\n", err)
+			return nil
+		}
+		var presses []bot.Press
+		for y := 0; y < b.Height(); y++ {
+			for x := 0; x < b.Width(); x++ {
+				for i := solution.Press(x, y); i > 0; i-- {
+					presses = append(presses, bot.Press{X: x, Y: y})
+				}
+			}
+		}
+		return presses
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "solverbot: %v\n", err)
+		os.Exit(1)
+	}
+}
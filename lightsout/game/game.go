@@ -0,0 +1,103 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/lightsout/solver"
+)
+
+// Game is one puzzle being played, shared by every front end: the board, the
+// board it started as and the moves made so far.
+type Game struct {
+	Board   *board.Board
+	Start   *board.Board
+	History *board.History
+	// Code identifies the puzzle being played.
+	Code string
+	// Level is the level being played, nil for generated puzzles.
+	Level *levels.Level
+
+	// system is built on the first hint.
+	system *solver.System
+}
+
+// New starts a game on b.
+func New(b *board.Board, code string) *Game {
+	return &Game{
+		Board:   b,
+		Start:   b.Clone(),
+		History: board.NewHistory(nil),
+		Code:    code,
+	}
+}
+
+// FromPuzzle starts a game on a generated puzzle.
+func FromPuzzle(p *puzzle.Puzzle) *Game {
+	return New(p.Board, p.Code())
+}
+
+// FromLevel starts a game on a level.
+func FromLevel(l *levels.Level) *Game {
+	g := New(l.Board(), l.Key())
+	g.Level = l
+	return g
+}
+
+// Restart starts the same puzzle again from the beginning.
+func (g *Game) Restart() *Game {
+	restarted := New(g.Start.Clone(), g.Code)
+	restarted.Level = g.Level
+	restarted.system = g.system
+	return restarted
+}
+
+// Press presses (x, y) and records the move.
+func (g *Game) Press(x, y int) bool {
+	return g.History.Press(g.Board, x, y)
+}
+
+// Undo takes back the last move.
+func (g *Game) Undo() bool {
+	_, ok := g.History.Undo(g.Board)
+	return ok
+}
+
+// Redo makes the last undone move again.
+func (g *Game) Redo() bool {
+	_, ok := g.History.Redo(g.Board)
+	return ok
+}
+
+// Solved reports whether every light is out.
+func (g *Game) Solved() bool {
+	return g.Board.IsSolved()
+}
+
+// Moves returns the number of moves made.
+func (g *Game) Moves() int {
+	return g.Board.Moves()
+}
+
+// Solve finds the shortest solution from the current board.
+func (g *Game) Solve() (*solver.Solution, error) {
+	if g.system == nil {
+		system, err := solver.NewSystem(g.Board.Width(), g.Board.Height(), g.Board.Rules())
+		if err != nil {
+			return nil, err
+		}
+		g.system = system
+	}
+	return g.system.Solve(g.Board)
+}
+
+// Label names the puzzle for people: the level's pack, title and par, or the
+// puzzle code.
+func (g *Game) Label() string {
+	if l := g.Level; l != nil {
+		return fmt.Sprintf("%s: %s, par %d", l.Pack().Title, l.Title, l.Par)
+	}
+	return g.Code
+}
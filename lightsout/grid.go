@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/explodes/gogames/colors"
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/game"
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/faiface/pixel"
+)
+
+var squareColors = colors.Palette{
+	pixel.RGB(1, 0.1, 0.1),
+	pixel.RGB(0.3, 0.3, 1),
+	pixel.RGB(0.4, 0.5, 0.2),
+}
+
+// Grid draws a game.
+type Grid struct {
+	*game.Game
+	colors []pixel.RGBA
+	// stateColors colors lights by state instead when they have more than
+	// two.
+	stateColors colors.Palette
+	cells       layout
+}
+
+func newGrid(g *game.Game, bounds pixel.Rect) *Grid {
+	b := g.Board
+	grid := &Grid{
+		Game:   g,
+		colors: squareColors.ColorGrid(b.Width(), b.Height()),
+		cells:  newLayout(b, bounds),
+	}
+	if states := b.Rules().States; states > 2 {
+		grid.stateColors = colors.Hues(states-1, 0, 0.8, 1)
+	}
+	return grid
+}
+
+func newPuzzleGrid(p *puzzle.Puzzle, bounds pixel.Rect) *Grid {
+	return newGrid(game.FromPuzzle(p), bounds)
+}
+
+func newLevelGrid(l *levels.Level, bounds pixel.Rect) *Grid {
+	return newGrid(game.FromLevel(l), bounds)
+}
+
+// lightColor returns the color of the light at (x, y) on b, black when off.
+func (g *Grid) lightColor(b *board.Board, x, y int) pixel.RGBA {
+	state := b.State(x, y)
+	switch {
+	case state == 0:
+		return pixel.RGB(0, 0, 0)
+	case g.stateColors != nil:
+		return g.stateColors.At(state - 1)
+	default:
+		return g.colors[b.Index(x, y)]
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"math"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/shapes"
+	"github.com/faiface/pixel"
+)
+
+// layout places a board's cells on the canvas.
+type layout interface {
+	// cell returns the outline of the cell at (x, y).
+	cell(x, y int) shapes.Shape
+	// cellAt returns the cell under pos, if any.
+	cellAt(pos pixel.Vec) (x, y int, ok bool)
+}
+
+func newLayout(b *board.Board, bounds pixel.Rect) layout {
+	if b.Rules().Hex {
+		return newHexLayout(b.Width(), b.Height(), bounds)
+	}
+	return &squareLayout{
+		width:  b.Width(),
+		height: b.Height(),
+		dx:     bounds.W() / float64(b.Width()),
+		dy:     bounds.H() / float64(b.Height()),
+	}
+}
+
+// squareLayout fills the canvas with a grid of rectangles.
+type squareLayout struct {
+	width, height int
+	dx, dy        float64
+}
+
+func (l *squareLayout) cell(x, y int) shapes.Shape {
+	min := pixel.V(float64(x)*l.dx, float64(y)*l.dy)
+	return shapes.Shape{min, pixel.V(min.X+l.dx, min.Y), pixel.V(min.X+l.dx, min.Y+l.dy), pixel.V(min.X, min.Y+l.dy)}
+}
+
+func (l *squareLayout) cellAt(pos pixel.Vec) (int, int, bool) {
+	x, y := int(math.Floor(pos.X/l.dx)), int(math.Floor(pos.Y/l.dy))
+	return x, y, x >= 0 && x < l.width && y >= 0 && y < l.height
+}
+
+// hexLayout fits pointy topped hexagons into the canvas, odd rows shifted
+// right by half a hexagon.
+type hexLayout struct {
+	width, height int
+	// radius is the distance from a hexagon's center to its corners.
+	radius float64
+	origin pixel.Vec
+}
+
+func newHexLayout(width, height int, bounds pixel.Rect) *hexLayout {
+	// a row of hexagons is sqrt(3) radii wide per cell plus half a cell for
+	// the shifted rows, and rows overlap by half a radius
+	w := math.Sqrt(3) * (float64(width) + 0.5)
+	h := 1.5*float64(height) + 0.5
+	radius := math.Min(bounds.W()/w, bounds.H()/h)
+	margin := pixel.V(bounds.W()-w*radius, bounds.H()-h*radius).Scaled(0.5)
+	return &hexLayout{
+		width:  width,
+		height: height,
+		radius: radius,
+		origin: bounds.Min.Add(margin).Add(pixel.V(math.Sqrt(3)/2*radius, radius)),
+	}
+}
+
+func (l *hexLayout) center(x, y int) pixel.Vec {
+	shift := 0.0
+	if y%2 == 1 {
+		shift = 0.5
+	}
+	return l.origin.Add(pixel.V(math.Sqrt(3)*l.radius*(float64(x)+shift), 1.5*l.radius*float64(y)))
+}
+
+func (l *hexLayout) cell(x, y int) shapes.Shape {
+	return shapes.RegularPolygon(l.center(x, y), 6, l.radius, math.Pi/2)
+}
+
+func (l *hexLayout) cellAt(pos pixel.Vec) (int, int, bool) {
+	// the nearest center is the hexagon containing pos
+	bestX, bestY, best := 0, 0, math.Inf(1)
+	for y := 0; y < l.height; y++ {
+		for x := 0; x < l.width; x++ {
+			if d := l.center(x, y).Sub(pos).Len(); d < best {
+				bestX, bestY, best = x, y, d
+			}
+		}
+	}
+	return bestX, bestY, best <= l.radius
+}
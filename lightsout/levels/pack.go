@@ -0,0 +1,233 @@
+package levels
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/solver"
+)
+
+//go:embed packs/*.json
+var builtin embed.FS
+
+// Pack is a set of levels played in order, read from a JSON file.
+type Pack struct {
+	// ID keys saved progress, so it shouldn't change once a pack is shared.
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Author string   `json:"author,omitempty"`
+	Levels []*Level `json:"levels"`
+}
+
+// Level is a starting board and the number of moves to aim for.
+type Level struct {
+	// ID keys saved progress within the pack, defaulting to the level's
+	// position from 1.
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	// Variant is the rules, as read by board.ParseRules, default classic.
+	// Lights must have a prime number of states, which the solver needs to
+	// check the level and set par.
+	Variant string `json:"variant,omitempty"`
+	// Width and Height are checked against Lights when given.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	// Lights draws the board top row first, as read by
+	// board.ParseWithRules: # or a state number for lights that are on and
+	// . for lights that are off.
+	Lights []string `json:"lights"`
+	// Par is the number of moves for three stars, default the fewest
+	// possible.
+	Par int `json:"par,omitempty"`
+
+	pack     *Pack
+	index    int
+	rules    board.Rules
+	start    *board.Board
+	shortest int
+}
+
+// Parse reads and checks a pack, filling in defaults.
+func Parse(data []byte) (*Pack, error) {
+	var p Pack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if p.ID == "" {
+		return nil, fmt.Errorf("pack has no id")
+	}
+	if strings.Contains(p.ID, "/") {
+		return nil, fmt.Errorf("pack id %q can't contain /", p.ID)
+	}
+	if p.Title == "" {
+		p.Title = p.ID
+	}
+	if len(p.Levels) == 0 {
+		return nil, fmt.Errorf("pack %s has no levels", p.ID)
+	}
+	ids := make(map[string]bool)
+	for i, l := range p.Levels {
+		if err := l.init(&p, i); err != nil {
+			return nil, fmt.Errorf("pack %s level %d: %v", p.ID, i+1, err)
+		}
+		if ids[l.ID] {
+			return nil, fmt.Errorf("pack %s level %d: duplicate id %q", p.ID, i+1, l.ID)
+		}
+		ids[l.ID] = true
+	}
+	return &p, nil
+}
+
+func (l *Level) init(p *Pack, index int) error {
+	l.pack, l.index = p, index
+	if l.ID == "" {
+		l.ID = strconv.Itoa(index + 1)
+	}
+	if l.Title == "" {
+		l.Title = fmt.Sprintf("Level %d", index+1)
+	}
+	if l.Variant == "" {
+		l.Variant = board.Classic.String()
+	}
+	var err error
+	if l.rules, err = board.ParseRules(l.Variant); err != nil {
+		return err
+	}
+	if solver.Supports(l.rules) != nil {
+		return fmt.Errorf("levels need a prime number of states, variant %s has %d", l.Variant, l.rules.States)
+	}
+	if l.start, err = board.ParseWithRules(strings.Join(l.Lights, "\n"), l.rules); err != nil {
+		return err
+	}
+	if l.Width != 0 && l.Width != l.start.Width() || l.Height != 0 && l.Height != l.start.Height() {
+		return fmt.Errorf("lights are %dx%d, expected %dx%d", l.start.Width(), l.start.Height(), l.Width, l.Height)
+	}
+	l.Width, l.Height = l.start.Width(), l.start.Height()
+	if l.start.IsSolved() {
+		return fmt.Errorf("every light is already off")
+	}
+	solution, err := solver.Solve(l.start)
+	if err != nil {
+		return err
+	}
+	l.shortest = solution.Count()
+	if l.Par == 0 {
+		l.Par = l.shortest
+	}
+	if l.Par < l.shortest {
+		return fmt.Errorf("par %d is below the shortest solution of %d moves", l.Par, l.shortest)
+	}
+	return nil
+}
+
+// Pack returns the pack the level belongs to.
+func (l *Level) Pack() *Pack {
+	return l.pack
+}
+
+// Index returns the level's position in its pack, from 0.
+func (l *Level) Index() int {
+	return l.index
+}
+
+// Key identifies the level in saved progress.
+func (l *Level) Key() string {
+	return l.pack.ID + "/" + l.ID
+}
+
+// Rules returns the level's parsed variant.
+func (l *Level) Rules() board.Rules {
+	return l.rules
+}
+
+// Board returns a fresh copy of the starting board.
+func (l *Level) Board() *board.Board {
+	return l.start.Clone()
+}
+
+// Shortest returns the fewest moves that solve the level.
+func (l *Level) Shortest() int {
+	return l.shortest
+}
+
+// Next returns the level after this one in its pack, or nil.
+func (l *Level) Next() *Level {
+	if l.index+1 < len(l.pack.Levels) {
+		return l.pack.Levels[l.index+1]
+	}
+	return nil
+}
+
+// LoadFS reads every .json pack in the root of fsys, sorted by ID.
+func LoadFS(fsys fs.FS) ([]*Pack, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	var packs []*Pack
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read pack %s: %v", name, err)
+		}
+		p, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pack %s: %v", name, err)
+		}
+		packs = append(packs, p)
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].ID < packs[j].ID
+	})
+	return packs, nil
+}
+
+// LoadDir reads every .json pack in dir.
+func LoadDir(dir string) ([]*Pack, error) {
+	return LoadFS(os.DirFS(dir))
+}
+
+// Builtin returns the packs that ship with the game.
+func Builtin() ([]*Pack, error) {
+	sub, err := fs.Sub(builtin, "packs")
+	if err != nil {
+		return nil, err
+	}
+	return LoadFS(sub)
+}
+
+// Merge adds extra packs to base. A pack with the ID of one in base replaces
+// it, so authors can iterate on a copy of a built in pack.
+func Merge(base, extra []*Pack) []*Pack {
+	merged := append([]*Pack(nil), base...)
+outer:
+	for _, p := range extra {
+		for i, existing := range merged {
+			if existing.ID == p.ID {
+				merged[i] = p
+				continue outer
+			}
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// Find returns the level with the given key.
+func Find(packs []*Pack, key string) *Level {
+	for _, p := range packs {
+		for _, l := range p.Levels {
+			if l.Key() == key {
+				return l
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package levels
+
+// MaxStars is the rating for solving a level in par.
+const MaxStars = 3
+
+// Stars rates a solve: three stars at or under par, two for up to half of par
+// more moves, but at least two more, and one for any other solve.
+func Stars(moves, par int) int {
+	slack := par / 2
+	if slack < 2 {
+		slack = 2
+	}
+	switch {
+	case moves <= par:
+		return 3
+	case moves <= par+slack:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Result is the best solve of a level.
+type Result struct {
+	Moves int `json:"moves"`
+	Stars int `json:"stars"`
+}
+
+// Progress is what has been solved, by level key.
+type Progress struct {
+	Levels map[string]Result `json:"levels"`
+}
+
+func NewProgress() *Progress {
+	return &Progress{Levels: make(map[string]Result)}
+}
+
+// Record saves a solve of l in moves and returns its result. It returns
+// true when the solve beat the previous best.
+func (p *Progress) Record(l *Level, moves int) (Result, bool) {
+	if p.Levels == nil {
+		p.Levels = make(map[string]Result)
+	}
+	r := Result{Moves: moves, Stars: Stars(moves, l.Par)}
+	best, ok := p.Levels[l.Key()]
+	if ok && best.Moves <= moves {
+		return r, false
+	}
+	p.Levels[l.Key()] = r
+	return r, true
+}
+
+// Result returns the best solve of l.
+func (p *Progress) Result(l *Level) (Result, bool) {
+	r, ok := p.Levels[l.Key()]
+	return r, ok
+}
+
+// Completed reports whether l has been solved.
+func (p *Progress) Completed(l *Level) bool {
+	_, ok := p.Levels[l.Key()]
+	return ok
+}
+
+// Unlocked reports whether l can be played: the first level of every pack
+// is open and each one after opens when the one before it is solved.
+func (p *Progress) Unlocked(l *Level) bool {
+	return l.index == 0 || p.Completed(l.pack.Levels[l.index-1])
+}
+
+// Stars returns the stars earned in a pack and the most there are to earn.
+func (p *Progress) Stars(pack *Pack) (earned, possible int) {
+	for _, l := range pack.Levels {
+		if r, ok := p.Result(l); ok {
+			earned += r.Stars
+		}
+		possible += MaxStars
+	}
+	return earned, possible
+}
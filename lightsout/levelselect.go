@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+var (
+	selectedLevelColor = pixel.RGB(1, 0.9, 0.2)
+	lockedLevelColor   = pixel.RGB(0.4, 0.4, 0.4)
+)
+
+// levelSelect lists the levels of one pack at a time, with the stars earned
+// on each. The arrow keys choose a level and switch packs.
+type levelSelect struct {
+	packs    []*levels.Pack
+	progress *levels.Progress
+	pack     int
+	level    int
+	txt      *text.Text
+}
+
+func newLevelSelect(packs []*levels.Pack, progress *levels.Progress, atlas *text.Atlas) *levelSelect {
+	s := &levelSelect{
+		packs:    packs,
+		progress: progress,
+		txt:      text.New(pixel.ZV, atlas),
+	}
+	s.selectFirstUnsolved()
+	return s
+}
+
+// show opens the list on l, or on the first unsolved level when l is nil.
+func (s *levelSelect) show(l *levels.Level) {
+	if l == nil {
+		s.selectFirstUnsolved()
+		return
+	}
+	for p, pack := range s.packs {
+		if pack == l.Pack() {
+			s.pack, s.level = p, l.Index()
+		}
+	}
+}
+
+func (s *levelSelect) selectFirstUnsolved() {
+	s.level = 0
+	if len(s.packs) == 0 {
+		return
+	}
+	for i, l := range s.packs[s.pack].Levels {
+		if !s.progress.Completed(l) {
+			s.level = i
+			return
+		}
+	}
+}
+
+// handleInput moves the selection and returns the level chosen with Enter.
+func (s *levelSelect) handleInput(win *pixelgl.Window) *levels.Level {
+	if len(s.packs) == 0 {
+		return nil
+	}
+	if win.JustPressed(pixelgl.KeyLeft) {
+		s.pack = (s.pack + len(s.packs) - 1) % len(s.packs)
+		s.selectFirstUnsolved()
+	}
+	if win.JustPressed(pixelgl.KeyRight) {
+		s.pack = (s.pack + 1) % len(s.packs)
+		s.selectFirstUnsolved()
+	}
+	count := len(s.packs[s.pack].Levels)
+	if win.JustPressed(pixelgl.KeyUp) || win.Repeated(pixelgl.KeyUp) {
+		s.level = (s.level + count - 1) % count
+	}
+	if win.JustPressed(pixelgl.KeyDown) || win.Repeated(pixelgl.KeyDown) {
+		s.level = (s.level + 1) % count
+	}
+	if win.JustPressed(pixelgl.KeyEnter) {
+		if l := s.packs[s.pack].Levels[s.level]; s.progress.Unlocked(l) {
+			return l
+		}
+	}
+	return nil
+}
+
+func (s *levelSelect) draw(t pixel.Target, center pixel.Vec) {
+	txt := s.txt
+	txt.Clear()
+	txt.Dot = txt.Orig
+	txt.Color = colornames.White
+	if len(s.packs) == 0 {
+		fmt.Fprintln(txt, "No level packs")
+	} else {
+		pack := s.packs[s.pack]
+		earned, possible := s.progress.Stars(pack)
+		fmt.Fprintf(txt, "< %s (%d/%d stars) >\n", pack.Title, earned, possible)
+		if pack.Author != "" {
+			fmt.Fprintf(txt, "by %s\n", pack.Author)
+		}
+		fmt.Fprintln(txt)
+		for i, l := range pack.Levels {
+			marker := "  "
+			switch {
+			case i == s.level:
+				marker = "> "
+				txt.Color = selectedLevelColor
+			case !s.progress.Unlocked(l):
+				txt.Color = lockedLevelColor
+			default:
+				txt.Color = colornames.White
+			}
+			fmt.Fprintf(txt, "%s%2d. %-16s ", marker, i+1, l.Title)
+			switch r, ok := s.progress.Result(l); {
+			case ok:
+				fmt.Fprintf(txt, "%s %d moves, par %d\n", starsText(r.Stars), r.Moves, l.Par)
+			case s.progress.Unlocked(l):
+				fmt.Fprintf(txt, "%s par %d\n", starsText(0), l.Par)
+			default:
+				fmt.Fprintln(txt, "locked")
+			}
+		}
+		txt.Color = colornames.White
+	}
+	fmt.Fprint(txt, "\narrows to choose, enter to play, M to go back")
+	txt.Draw(t, pixel.IM.Moved(center.Sub(txt.Bounds().Center())))
+}
+
+// starsText draws a rating as [**.].
+func starsText(stars int) string {
+	return "[" + strings.Repeat("*", stars) + strings.Repeat(".", levels.MaxStars-stars) + "]"
+}
@@ -3,40 +3,43 @@ package main
 import (
 	"fmt"
 	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/colors"
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/highscores"
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/persistence"
+	"github.com/explodes/gogames/rng"
+	"github.com/explodes/gogames/shapes"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
 	"golang.org/x/image/colornames"
+	"golang.org/x/image/font/basicfont"
 	"math"
-	"math/rand"
 	"os"
 	"time"
 )
 
 const (
-	title                     = "Lights Out"
-	width, height             = 700, 700
-	canvasWidth, canvasHeight = 700, 700
-	maxFps                    = 24
-
-	gridSideLength = 8
-	gridSquares    = gridSideLength * gridSideLength
+	title = "Lights Out"
 
 	starPoints                 = 5
 	starRotateDegreesPerSecond = 96
 	starInnerRadiusFactor      = 0.5
 	starColorTransitionSpeed   = 0.5
+
+	hintThickness = 4
 )
 
-var squareColors = []pixel.RGBA{
-	pixel.RGB(1, 0.1, 0.1),
-	pixel.RGB(0.3, 0.3, 1),
-	pixel.RGB(0.4, 0.5, 0.2),
-}
+var hintColor = pixel.RGB(1, 1, 1)
 
-type Grid struct {
-	squares [gridSquares]bool
-	colors  [gridSquares]pixel.RGBA
+// hint is the next cell the solver suggests pressing.
+type hint struct {
+	x, y  int
+	shown bool
 }
 
 type Star struct {
@@ -49,11 +52,7 @@ type Star struct {
 func (s *Star) Update(dt float64) {
 	s.rotationDeg += starRotateDegreesPerSecond * dt
 	s.colorTransition += starColorTransitionSpeed * 360 * dt
-	s.color.G = 0.5 + 0.25*(1+math.Cos(degToRad(s.colorTransition)))
-}
-
-func degToRad(d float64) float64 {
-	return d * math.Pi / 180
+	s.color.G = 0.5 + 0.25*(1+math.Cos(games.DegToRad(s.colorTransition)))
 }
 
 func NewStar(width, height float64) *Star {
@@ -68,103 +67,197 @@ func NewStar(width, height float64) *Star {
 }
 
 func (s *Star) createDrawing(width, height float64) *imdraw.IMDraw {
-	const pointDegDelta = 360.0 / (starPoints)
-	const pointInnerDegDelta = 360.0 / (2.0 * starPoints)
-	//fmt.Println(pointDegDelta)
-
-	maxMagnitude := math.Min(width, height) / 2
-	innerMagnitude := maxMagnitude * starInnerRadiusFactor
+	center := pixel.V(width/2, height/2)
+	radius := math.Min(width, height) / 2
 
 	imd := imdraw.New(nil)
 	imd.Color = s.color
-
-	for point := 0; point < starPoints; point++ {
-		degrees := float64(point)*pointDegDelta + 90
-
-		x0, y0 := math.Cos(degToRad(degrees+pointInnerDegDelta))*innerMagnitude+width/2, math.Sin(degToRad(degrees+pointInnerDegDelta))*innerMagnitude+height/2
-		x1, y1 := math.Cos(degToRad(degrees))*maxMagnitude+width/2, math.Sin(degToRad(degrees))*maxMagnitude+height/2
-		x2, y2 := math.Cos(degToRad(degrees-pointInnerDegDelta))*innerMagnitude+width/2, math.Sin(degToRad(degrees-pointInnerDegDelta))*innerMagnitude+height/2
-
-		imd.Push(pixel.V(x0, y0))
-		imd.Push(pixel.V(x1, y1))
-		imd.Push(pixel.V(x2, y2))
-		imd.Polygon(0)
-
-		imd.Push(pixel.V(x0, y0))
-		imd.Push(pixel.V(width/2, height/2))
-		imd.Push(pixel.V(x2, y2))
-		imd.Polygon(0)
-	}
+	shapes.Star(center, starPoints, radius, starInnerRadiusFactor, math.Pi/2).Fill(imd)
 
 	return imd
 }
 
 func (s *Star) Draw(canvas *pixelgl.Canvas) {
 	canvas.SetColorMask(s.color)
-	canvas.SetMatrix(pixel.IM.Rotated(canvas.Bounds().Center(), degToRad(s.rotationDeg)))
+	canvas.SetMatrix(pixel.IM.Rotated(canvas.Bounds().Center(), games.DegToRad(s.rotationDeg)))
 	s.drawing.Draw(canvas)
 }
 
-func newGrid() *Grid {
-	g := &Grid{}
-
-	for i := 0; i < gridSquares; i++ {
-		g.squares[i] = true
-		g.colors[i] = squareColor(i)
+func run(cfg Config) {
+	winCfg := pixelgl.WindowConfig{
+		Title:  title,
+		Bounds: pixel.R(0, 0, float64(cfg.Window.Width), float64(cfg.Window.Height)),
+		VSync:  true,
+	}
+	win, err := pixelgl.NewWindow(winCfg)
+	if err != nil {
+		exitWith(err, "unable to create window")
 	}
 
-	return g
-}
-
-func squareColor(index int) pixel.RGBA {
+	canvas := pixelgl.NewCanvas(pixel.R(0, 0, float64(cfg.Window.CanvasWidth), float64(cfg.Window.CanvasHeight)))
 
-	var mod int
-	// todo(evan): these colors could still make vertical columns of the same color (i.e. 6x6 board)
-	if gridSideLength%2 == 0 {
-		mod = 3
-	} else {
-		mod = 2
-	}
+	imd := imdraw.New(nil)
+	imd.Precision = 32
 
-	return squareColors[index%mod]
-}
+	fpsLimit := games.NewFpsLimiter(cfg.Window.MaxFps)
 
-func run() {
+	store, err := newSaveStore()
+	if err != nil {
+		fmt.Printf("unable to open saved games: %v\n", err)
+	}
 
-	rand.Seed(time.Now().UnixNano())
+	gridSideLength := cfg.GridSideLength
+	rules, _ := board.ParseRules(cfg.Variant)
+	difficulty, _ := puzzle.ParseDifficulty(cfg.Difficulty)
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	switch {
+	case cfg.Puzzle != "":
+		spec, _ := puzzle.ParseCode(cfg.Puzzle)
+		gridSideLength, rules, difficulty, seed = spec.Width, spec.Rules, spec.Difficulty, spec.Seed
+	case cfg.Daily:
+		seed = puzzle.DailySeed(time.Now())
+	}
+	generator, err := puzzle.NewGenerator(gridSideLength, gridSideLength, rules)
+	if err != nil {
+		exitWith(err, "unable to create puzzles")
+	}
+	// seeds for the games after the first, so a seed replays a whole session
+	seeds := rng.New(seed)
 
-	cfg := pixelgl.WindowConfig{
-		Title:  title,
-		Bounds: pixel.R(0, 0, width, height),
-		VSync:  true,
+	packs, err := levels.Builtin()
+	if err != nil {
+		exitWith(err, "unable to load built in levels")
 	}
-	win, err := pixelgl.NewWindow(cfg)
+	if cfg.Levels != "" {
+		extra, err := levels.LoadDir(cfg.Levels)
+		if err != nil {
+			exitWith(err, "unable to load levels from %s", cfg.Levels)
+		}
+		packs = levels.Merge(packs, extra)
+	}
+	progress := levels.NewProgress()
+	progressStore, err := newProgressStore()
 	if err != nil {
-		exitWith(err, "unable to create window")
+		fmt.Printf("unable to open level progress: %v\n", err)
+	} else if err := progressStore.Load(progressSlot, progress); err != nil && err != persistence.ErrNoSave {
+		fmt.Printf("unable to load level progress: %v\n", err)
 	}
 
-	canvas := pixelgl.NewCanvas(pixel.R(0, 0, canvasWidth, canvasHeight))
+	first, err := generator.Generate(seed, difficulty)
+	if err != nil {
+		exitWith(err, "unable to create a puzzle")
+	}
+	grid := newPuzzleGrid(first, canvas.Bounds())
+	elapsed := 0.0
+	// asking for a particular puzzle replaces the saved game
+	if store != nil && cfg.Puzzle == "" && !cfg.Daily {
+		if savedGrid, savedElapsed, err := loadGame(store, canvas.Bounds(), packs); err != nil {
+			if err != persistence.ErrNoSave {
+				fmt.Printf("unable to load saved game: %v\n", err)
+			}
+		} else if b := savedGrid.Board; savedGrid.Level == nil && (b.Width() != gridSideLength || b.Height() != gridSideLength || b.Rules().String() != rules.String()) {
+			fmt.Printf("saved game is %dx%d %s, expected %dx%d %s\n", b.Width(), b.Height(), b.Rules(), gridSideLength, gridSideLength, rules)
+		} else {
+			grid, elapsed = savedGrid, savedElapsed
+		}
+	}
 
-	imd := imdraw.New(nil)
-	imd.Precision = 32
+	scores, err := highscores.Open("lightsout")
+	if err != nil {
+		fmt.Printf("unable to open high scores: %v\n", err)
+	}
+	scoreMode := fmt.Sprintf("%dx%d %s", gridSideLength, gridSideLength, difficulty)
+	if rules.String() != board.Classic.String() {
+		scoreMode = fmt.Sprintf("%s %s", rules, scoreMode)
+	}
+	// every solve is ranked by fewest moves and, on its own board, by
+	// fastest time
+	fastestMode := scoreMode + " fastest"
+
+	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
+	scoresTxt := text.New(pixel.ZV, atlas)
+	var prompt *highscores.NamePrompt
+	showScores := func(movesRank, fastestRank int) {
+		scoresTxt.Clear()
+		scoresTxt.Dot = scoresTxt.Orig
+		fmt.Fprintf(scoresTxt, "Fewest moves, %s\n\n", scoreMode)
+		highscores.WriteEntries(scoresTxt, scores.Entries(scoreMode), movesRank)
+		fmt.Fprintf(scoresTxt, "\nFastest, %s\n\n", scoreMode)
+		highscores.WriteEntries(scoresTxt, scores.Entries(fastestMode), fastestRank)
+		fmt.Fprint(scoresTxt, "\nR to play again")
+	}
+
+	levelTxt := text.New(pixel.ZV, atlas)
+	showLevelResult := func(result levels.Result, improved bool) {
+		l := grid.Level
+		levelTxt.Clear()
+		levelTxt.Dot = levelTxt.Orig
+		fmt.Fprintf(levelTxt, "%s complete!\n\n", l.Title)
+		fmt.Fprintf(levelTxt, "%s %d moves, par %d\n", starsText(result.Stars), result.Moves, l.Par)
+		if best, _ := progress.Result(l); !improved {
+			fmt.Fprintf(levelTxt, "best %s %d moves\n", starsText(best.Stars), best.Moves)
+		}
+		if l.Next() != nil {
+			fmt.Fprint(levelTxt, "\nN for the next level")
+		}
+		fmt.Fprint(levelTxt, "\nR to retry, M for levels, P to replay")
+	}
+	levelMenu := newLevelSelect(packs, progress, atlas)
+	selecting := false
 
-	fpsLimit := games.NewFpsLimiter(maxFps)
+	star := NewStar(canvas.Bounds().W(), canvas.Bounds().H())
 
-	grid := newGrid()
-	star := NewStar(canvasWidth, canvasHeight)
+	var next hint
+	status := ""
 
 	//last := time.Now()
 
-	// width and height in CANVAS pixels of a given square
-	const dx = float64(canvasWidth) / float64(gridSideLength)
-	const dy = float64(canvasHeight) / float64(gridSideLength)
+	// canvas pixels per WINDOW pixel
+	toCanvas := pixel.V(canvas.Bounds().W()/win.Bounds().W(), canvas.Bounds().H()/win.Bounds().H())
+
+	// drawLights draws the lights fading from before to after.
+	drawLights := func(before, after *board.Board, fade float64) {
+		for y := 0; y < after.Height(); y++ {
+			for x := 0; x < after.Width(); x++ {
+				if !before.Light(x, y) && !after.Light(x, y) {
+					continue
+				}
+				imd.Color = colors.Lerp(grid.lightColor(before, x, y), grid.lightColor(after, x, y), fade)
+				grid.cells.cell(x, y).Fill(imd)
+			}
+		}
+	}
+	outlineCell := func(x, y int) {
+		cell := grid.cells.cell(x, y)
+		imd.Color = hintColor
+		cell.Scaled(cell.Centroid(), 0.85).Stroke(imd, hintThickness)
+	}
 
-	// width and height in WINDOW pixels of a given square
-	const ssx = float64(width) / float64(gridSideLength)
-	const ssy = float64(height) / float64(gridSideLength)
+	var list *moveList
+	movesBounds := pixel.R(canvas.Bounds().Max.X-160, canvas.Bounds().Min.Y, canvas.Bounds().Max.X, canvas.Bounds().Max.Y)
+	var playback *replay
+
+	winner := grid.Solved()
+	// play starts a new grid.
+	play := func(g *Grid) {
+		grid = g
+		winner = false
+		elapsed = 0
+		next.shown = false
+		status = ""
+		playback = nil
+		selecting = false
+	}
 
-	moves := 0
-	winner := false
+	if winner && grid.Level != nil {
+		r, _ := progress.Result(grid.Level)
+		showLevelResult(r, true)
+	} else if winner && scores != nil {
+		showScores(0, 0)
+	}
 
 	last := time.Now()
 
@@ -173,50 +266,147 @@ func run() {
 		dt := time.Since(last).Seconds()
 		last = time.Now()
 
+		if prompt != nil {
+			prompt.HandleInput(win)
+			prompt.Update(dt)
+			if prompt.Done() {
+				movesRank, fastestRank := 0, 0
+				if !prompt.Cancelled() {
+					entry := highscores.Entry{
+						Name:  prompt.Name(),
+						Score: grid.Moves(),
+						Time:  secondsToDuration(elapsed),
+					}
+					if movesRank, err = scores.Add(scoreMode, highscores.LowestFirst, entry); err != nil {
+						fmt.Printf("unable to save high score: %v\n", err)
+					}
+					if fastestRank, err = scores.Add(fastestMode, highscores.FastestFirst, entry); err != nil {
+						fmt.Printf("unable to save high score: %v\n", err)
+					}
+				}
+				prompt = nil
+				showScores(movesRank, fastestRank)
+			}
+			goto update
+		}
+
+		if win.JustPressed(pixelgl.KeyM) {
+			selecting = !selecting
+			levelMenu.show(grid.Level)
+		}
+		if selecting {
+			if win.JustPressed(pixelgl.KeyEscape) {
+				selecting = false
+			} else if l := levelMenu.handleInput(win); l != nil {
+				play(newLevelGrid(l, canvas.Bounds()))
+			}
+			goto update
+		}
+
 		if win.JustPressed(pixelgl.KeyR) {
-			grid = newGrid()
-			winner = false
-			moves = 0
+			if grid.Level != nil {
+				play(newGrid(grid.Restart(), canvas.Bounds()))
+			} else if p, err := generator.Generate(seeds.Int63(), difficulty); err != nil {
+				fmt.Printf("unable to create a puzzle: %v\n", err)
+			} else {
+				play(newPuzzleGrid(p, canvas.Bounds()))
+			}
 		}
 
-		if !winner && win.JustPressed(pixelgl.MouseButton1) {
-			pos := win.MousePosition()
-			x := int(pos.X / ssx)
-			y := int(pos.Y / ssy)
+		if winner && grid.Level != nil && grid.Level.Next() != nil && win.JustPressed(pixelgl.KeyN) {
+			play(newLevelGrid(grid.Level.Next(), canvas.Bounds()))
+		}
 
-			i := x + y*gridSideLength
-			if i >= gridSquares {
-				goto update
+		if win.JustPressed(pixelgl.KeyL) {
+			if list == nil {
+				list = newMoveList(atlas, movesBounds)
+			} else {
+				list = nil
 			}
+		}
+		if list != nil {
+			list.handleInput(win, len(grid.History.Moves()))
+			list.follow(grid.History.Cursor(), len(grid.History.Moves()))
+		}
 
-			moves++
+		if winner && win.JustPressed(pixelgl.KeyP) {
+			if playback == nil {
+				playback = newReplay(grid.Start, grid.History.Applied())
+			} else {
+				playback = nil
+			}
+		}
+		if playback != nil {
+			if win.JustPressed(pixelgl.KeySpace) {
+				playback.paused = !playback.paused
+			}
+			if win.JustPressed(pixelgl.KeyRight) {
+				playback.paused = true
+				playback.forward()
+			}
+			if win.JustPressed(pixelgl.KeyLeft) {
+				playback.paused = true
+				playback.back()
+			}
+			playback.Update(dt)
+		}
 
-			grid.squares[i] = !grid.squares[i]
-			if x > 0 {
-				index := (x - 1) + y*gridSideLength
-				grid.squares[index] = !grid.squares[index]
+		if !winner && win.JustPressed(pixelgl.KeyZ) {
+			if grid.Undo() {
+				next.shown = false
+				status = ""
 			}
-			if x < gridSideLength-1 {
-				index := (x + 1) + y*gridSideLength
-				grid.squares[index] = !grid.squares[index]
+		}
+		if !winner && win.JustPressed(pixelgl.KeyY) {
+			if grid.Redo() {
+				next.shown = false
+				status = ""
 			}
-			if y > 0 {
-				index := x + (y-1)*gridSideLength
-				grid.squares[index] = !grid.squares[index]
+		}
+
+		if !winner && win.JustPressed(pixelgl.KeyH) {
+			next.shown = false
+			solution, err := grid.Solve()
+			if err != nil {
+				status = "unsolvable"
+			} else if x, y, ok := solution.Next(); ok {
+				next = hint{x: x, y: y, shown: true}
+				status = fmt.Sprintf("%d presses left", solution.Count())
 			}
-			if y < gridSideLength-1 {
-				index := x + (y+1)*gridSideLength
-				grid.squares[index] = !grid.squares[index]
+		}
+
+		if !winner && win.JustPressed(pixelgl.MouseButton1) {
+			pos := win.MousePosition()
+			if x, y, ok := grid.cells.cellAt(pixel.V(pos.X*toCanvas.X, pos.Y*toCanvas.Y)); ok && grid.Press(x, y) {
+				next.shown = false
+				status = ""
 			}
+		}
 
+		if !winner {
+			elapsed += dt
 		}
 
-		for _, square := range grid.squares {
-			if square {
-				goto update
+		if !winner && grid.Solved() {
+			winner = true
+			moves := grid.Moves()
+			if grid.Level != nil {
+				result, improved := progress.Record(grid.Level, moves)
+				if improved && progressStore != nil {
+					if err := progressStore.Save(progressSlot, progress); err != nil {
+						fmt.Printf("unable to save level progress: %v\n", err)
+					}
+				}
+				showLevelResult(result, improved)
+			} else if scores != nil {
+				taken := secondsToDuration(elapsed)
+				if scores.Qualifies(scoreMode, highscores.LowestFirst, moves, taken) || scores.Qualifies(fastestMode, highscores.FastestFirst, moves, taken) {
+					prompt = highscores.NewNamePrompt(fmt.Sprintf("Solved in %d moves! Enter your name:", moves), scores.LastName(), 12, atlas)
+				} else {
+					showScores(0, 0)
+				}
 			}
 		}
-		winner = true
 
 	update:
 		star.Update(dt)
@@ -225,59 +415,95 @@ func run() {
 		canvas.Clear(colornames.Black)
 		imd.Clear()
 
-		if winner {
+		switch {
+		case selecting:
+		case playback != nil:
+			drawLights(playback.before, playback.after, playback.fade())
+			if m, ok := playback.move(); ok {
+				outlineCell(m.X, m.Y)
+			}
+		case winner:
 			star.Draw(canvas)
-		} else {
-			canvas.SetColorMask(colornames.White)
-			canvas.SetMatrix(pixel.IM)
+		default:
 			// draw game into image
-			for i := 0; i < gridSquares; i++ {
-				if !grid.squares[i] {
-					continue
-				}
-
-				x := float64(i%gridSideLength) * dx
-				y := float64(i/gridSideLength) * dy
-				bottomleft := pixel.V(x, y)
-				topright := pixel.V(x+dx, y+dy)
-
-				imd.Color = grid.colors[i]
-				imd.Push(bottomleft, topright)
-				imd.Rectangle(0)
+			drawLights(grid.Board, grid.Board, 1)
+			if next.shown {
+				outlineCell(next.x, next.y)
 			}
 		}
+		if list != nil && !selecting {
+			list.drawBackground(imd)
+		}
 
 		// draw image into canvas
+		canvas.SetColorMask(colornames.White)
+		canvas.SetMatrix(pixel.IM)
 		imd.Draw(canvas)
 
+		if list != nil && !selecting {
+			list.draw(canvas, grid.History.Moves(), grid.History.Cursor())
+		}
+
+		switch {
+		case selecting:
+			levelMenu.draw(canvas, canvas.Bounds().Center())
+		case winner && playback == nil && grid.Level != nil:
+			levelTxt.Draw(canvas, pixel.IM.Moved(canvas.Bounds().Center().Sub(levelTxt.Bounds().Center())))
+		case winner && playback == nil && scores != nil:
+			if prompt != nil {
+				prompt.Draw(canvas, canvas.Bounds().Center())
+			} else {
+				scoresTxt.Draw(canvas, pixel.IM.Moved(canvas.Bounds().Center().Sub(scoresTxt.Bounds().Center())))
+			}
+		}
+
 		// draw canvas into window
 		games.DrawCanvasInWindow(colornames.White, win, canvas)
 
 		fpsLimit.WaitForNextFrame()
-		win.SetTitle(fmt.Sprintf("%s | moves: %d | fps %.0f", title, moves, fpsLimit.CurrentFrameFps()))
+		label := grid.Label()
+		if selecting {
+			win.SetTitle(fmt.Sprintf("%s | levels", title))
+		} else if playback != nil {
+			state := "space to pause, arrows to step"
+			if playback.paused {
+				state = "paused"
+			} else if playback.done() {
+				state = "P to stop"
+			}
+			win.SetTitle(fmt.Sprintf("%s | %s | replay %d/%d | %s", title, label, playback.step, len(playback.moves), state))
+		} else if status != "" {
+			win.SetTitle(fmt.Sprintf("%s | %s | moves: %d | hint: %s | fps %.0f", title, label, grid.Moves(), status, fpsLimit.CurrentFrameFps()))
+		} else {
+			win.SetTitle(fmt.Sprintf("%s | %s | moves: %d | fps %.0f", title, label, grid.Moves(), fpsLimit.CurrentFrameFps()))
+		}
+	}
+
+	if store != nil {
+		if err := saveGame(store, grid, elapsed); err != nil {
+			fmt.Printf("unable to save game: %v\n", err)
+		}
 	}
 }
 
-func drawStar(imd *imdraw.IMDraw, bounds pixel.Rect) {
-	imd.Color = randomNiceColor()
-	imd.Push(bounds.Min, bounds.Max)
-	imd.Rectangle(0)
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
 }
 
-func randomNiceColor() pixel.RGBA {
-again:
-	r := rand.Float64()
-	g := rand.Float64()
-	b := rand.Float64()
-	magnitude := math.Sqrt(r*r + g*g + b*b)
-	if magnitude == 0 {
-		goto again
-	}
-	return pixel.RGB(r/magnitude, g/magnitude, b/magnitude)
+func drawStar(imd *imdraw.IMDraw, rng *rng.RNG, bounds pixel.Rect) {
+	imd.Color = colors.RandomNice(rng)
+	imd.Push(bounds.Min, bounds.Max)
+	imd.Rectangle(0)
 }
 
 func main() {
-	pixelgl.Run(run)
+	cfg := defaultConfig()
+	if err := config.Load("lightsout", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "invalid config")
+	}
+	pixelgl.Run(func() {
+		run(cfg)
+	})
 }
 
 func exitWith(err error, msg string, args ...interface{}) {
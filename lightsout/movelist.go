@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+const moveListMargin = 8
+
+var (
+	moveListBackground = pixel.RGBA{R: 0, G: 0, B: 0, A: 0.75}
+	undoneMoveColor    = pixel.RGB(0.5, 0.5, 0.5)
+)
+
+// moveList shows the moves of a game in a panel, scrolled with the mouse
+// wheel. Undone moves are grayed out and the last applied move is marked.
+type moveList struct {
+	txt    *text.Text
+	bounds pixel.Rect
+	// first is the first move shown.
+	first int
+	// cursor is the cursor the list last followed.
+	cursor int
+}
+
+func newMoveList(atlas *text.Atlas, bounds pixel.Rect) *moveList {
+	return &moveList{
+		txt:    text.New(pixel.ZV, atlas),
+		bounds: bounds,
+		cursor: -1,
+	}
+}
+
+// rows returns how many moves fit in the panel, below the heading.
+func (l *moveList) rows() int {
+	lineHeight := l.txt.Atlas().LineHeight()
+	if lineHeight <= 0 {
+		return 1
+	}
+	rows := int((l.bounds.H()-2*moveListMargin)/lineHeight) - 2
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
+
+// scroll moves the list by lines, clamped to the moves there are.
+func (l *moveList) scroll(lines, moves int) {
+	l.first += lines
+	if max := moves - l.rows(); l.first > max {
+		l.first = max
+	}
+	if l.first < 0 {
+		l.first = 0
+	}
+}
+
+// handleInput scrolls with the mouse wheel.
+func (l *moveList) handleInput(win *pixelgl.Window, moves int) {
+	if dy := win.MouseScroll().Y; dy != 0 {
+		// scrolling up shows earlier moves
+		l.scroll(-int(dy), moves)
+	}
+}
+
+// follow scrolls to keep the last applied move in view when it changes.
+func (l *moveList) follow(cursor, moves int) {
+	if cursor == l.cursor {
+		return
+	}
+	l.cursor = cursor
+	if cursor-1 < l.first {
+		l.first = cursor - 1
+	} else if cursor > l.first+l.rows() {
+		l.first = cursor - l.rows()
+	}
+	l.scroll(0, moves)
+}
+
+func (l *moveList) drawBackground(imd *imdraw.IMDraw) {
+	imd.Color = moveListBackground
+	imd.Push(l.bounds.Min, l.bounds.Max)
+	imd.Rectangle(0)
+}
+
+func (l *moveList) draw(t pixel.Target, moves []board.Move, cursor int) {
+	txt := l.txt
+	txt.Clear()
+	txt.Dot = txt.Orig
+	txt.Color = colornames.White
+	fmt.Fprintf(txt, "Moves %d/%d\n\n", cursor, len(moves))
+	last := l.first + l.rows()
+	if last > len(moves) {
+		last = len(moves)
+	}
+	for i := l.first; i < last; i++ {
+		marker := " "
+		if i == cursor-1 {
+			marker = ">"
+		}
+		if i >= cursor {
+			txt.Color = undoneMoveColor
+		}
+		fmt.Fprintf(txt, "%s%3d. %s\n", marker, i+1, moves[i])
+	}
+	top := pixel.V(l.bounds.Min.X+moveListMargin, l.bounds.Max.Y-moveListMargin-txt.Atlas().Ascent())
+	txt.Draw(t, pixel.IM.Moved(top))
+}
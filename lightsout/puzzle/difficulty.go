@@ -0,0 +1,69 @@
+package puzzle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difficulty rates a puzzle by the length of its shortest solution.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+var difficultyNames = []string{"easy", "medium", "hard", "expert"}
+
+// difficultyShares is the largest share of the most useful presses, one per
+// cell on a two state board, that each difficulty needs in its shortest
+// solution.
+var difficultyShares = []float64{0.15, 0.3, 0.45, 1}
+
+func (d Difficulty) String() string {
+	if d < Easy || d > Expert {
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+	return difficultyNames[d]
+}
+
+// ParseDifficulty reads a difficulty written by String.
+func ParseDifficulty(s string) (Difficulty, error) {
+	for d, name := range difficultyNames {
+		if strings.EqualFold(s, name) {
+			return Difficulty(d), nil
+		}
+	}
+	return Easy, fmt.Errorf("unknown difficulty %q", s)
+}
+
+// Rate returns the difficulty of a puzzle whose shortest solution is presses
+// long, where most is the number of cells times one less than the number of
+// states.
+func Rate(presses, most int) Difficulty {
+	share := float64(presses) / float64(most)
+	for d, most := range difficultyShares {
+		if share <= most {
+			return Difficulty(d)
+		}
+	}
+	return Expert
+}
+
+// pressRange returns how many presses a puzzle of difficulty d has, at
+// least one, where most is as for Rate.
+func (d Difficulty) pressRange(most int) (min, max int) {
+	if d > Easy {
+		min = int(difficultyShares[d-1]*float64(most)) + 1
+	}
+	max = int(difficultyShares[d] * float64(most))
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
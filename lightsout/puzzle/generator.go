@@ -0,0 +1,239 @@
+package puzzle
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/solver"
+	"github.com/explodes/gogames/rng"
+)
+
+// maxAttempts bounds the search for a puzzle of the requested difficulty.
+// Boards with a null space can have shorter solutions than the presses used
+// to make them, and some difficulties don't exist on tiny boards.
+const maxAttempts = 100
+
+// maxGenerateWork bounds the solver work of one Generate, in the units of
+// solver.System.SearchWork, so that boards with big null spaces get fewer
+// attempts. Every board gets at least minAttempts.
+const (
+	maxGenerateWork = 1 << 25
+	minAttempts     = 4
+)
+
+// Puzzle is a generated board and what it takes to solve it.
+type Puzzle struct {
+	Seed     int64
+	Board    *board.Board
+	Solution *solver.Solution
+	// Target is the difficulty asked for and Difficulty the rating of the
+	// board made. They differ only when Target isn't possible at this size.
+	Target     Difficulty
+	Difficulty Difficulty
+}
+
+// Presses returns the length of the shortest solution.
+func (p *Puzzle) Presses() int {
+	return p.Solution.Count()
+}
+
+// Code identifies the puzzle so it can be shared and generated again.
+func (p *Puzzle) Code() string {
+	return Spec{
+		Rules:      p.Board.Rules(),
+		Width:      p.Board.Width(),
+		Height:     p.Board.Height(),
+		Difficulty: p.Target,
+		Seed:       p.Seed,
+	}.String()
+}
+
+// Spec is everything needed to generate a puzzle.
+type Spec struct {
+	Rules         board.Rules
+	Width, Height int
+	Difficulty    Difficulty
+	Seed          int64
+}
+
+// String writes the spec as a puzzle code such as 8x8-hard-12345, prefixed
+// with the rules and a slash when they aren't classic.
+func (s Spec) String() string {
+	code := fmt.Sprintf("%dx%d-%s-%d", s.Width, s.Height, s.Difficulty, s.Seed)
+	if rules := s.Rules.String(); rules != board.Classic.String() {
+		code = rules + "/" + code
+	}
+	return code
+}
+
+// ParseCode reads a code written by Puzzle.Code.
+func ParseCode(code string) (Spec, error) {
+	spec := Spec{Rules: board.Classic}
+	if i := strings.LastIndex(code, "/"); i >= 0 {
+		rules, err := board.ParseRules(code[:i])
+		if err != nil {
+			return Spec{}, err
+		}
+		spec.Rules, code = rules, code[i+1:]
+	}
+	parts := strings.SplitN(code, "-", 3)
+	if len(parts) != 3 {
+		return Spec{}, fmt.Errorf("invalid puzzle code %q", code)
+	}
+	if _, err := fmt.Sscanf(parts[0], "%dx%d", &spec.Width, &spec.Height); err != nil || spec.Width <= 0 || spec.Height <= 0 {
+		return Spec{}, fmt.Errorf("invalid puzzle size %q", parts[0])
+	}
+	var err error
+	if spec.Difficulty, err = ParseDifficulty(parts[1]); err != nil {
+		return Spec{}, err
+	}
+	if spec.Seed, err = strconv.ParseInt(parts[2], 10, 64); err != nil {
+		return Spec{}, fmt.Errorf("invalid puzzle seed %q", parts[2])
+	}
+	return spec, nil
+}
+
+// Generator makes solvable puzzles of one board size and set of rules.
+type Generator struct {
+	system *solver.System
+	// most is the number of presses that can be useful, one less than the
+	// number of states for each cell.
+	most int
+	// hardest is the highest difficulty any board can have.
+	hardest  Difficulty
+	attempts int
+}
+
+func NewGenerator(width, height int, rules board.Rules) (*Generator, error) {
+	system, err := solver.NewSystem(width, height, rules)
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{
+		system:   system,
+		most:     width * height * (rules.States - 1),
+		attempts: maxAttempts,
+	}
+	g.hardest = Rate(longest(system), g.most)
+	if work := system.SearchWork(); work > 0 && maxGenerateWork/work < g.attempts {
+		g.attempts = maxGenerateWork / work
+		if g.attempts < minAttempts {
+			g.attempts = minAttempts
+		}
+	}
+	return g, nil
+}
+
+// longest bounds the length of the shortest solution of any board. Solving
+// only with the pivot cells presses at most rank cells, and adding every
+// combination of the null space presses each cell it can change (k-1)/2
+// times on average, so some solution is no longer than that average.
+func longest(s *solver.System) int {
+	k := s.Rules().States
+	cells := s.Width() * s.Height()
+	pivots := s.Rank() * (k - 1)
+
+	changed := make([]bool, cells)
+	for _, v := range s.NullSpace() {
+		for i, presses := range v {
+			if presses != 0 {
+				changed[i] = true
+			}
+		}
+	}
+	twice := 0
+	for _, c := range changed {
+		if c {
+			twice += k - 1
+		} else {
+			twice += 2 * (k - 1)
+		}
+	}
+	if average := twice / 2; average < pivots {
+		return average
+	}
+	return pivots
+}
+
+// System returns the solver the generator rates puzzles with.
+func (g *Generator) System() *solver.System {
+	return g.system
+}
+
+// Generate makes the puzzle for seed at difficulty d. The same size, seed
+// and difficulty always make the same puzzle. Every puzzle is solvable
+// because it is made by pressing cells of a dark board. Difficulties harder
+// than any board of this kind get the hardest there is.
+func (g *Generator) Generate(seed int64, d Difficulty) (*Puzzle, error) {
+	rng := rng.New(seed)
+	width, height, rules := g.system.Width(), g.system.Height(), g.system.Rules()
+	cells, most := width*height, g.most
+	aim := d
+	if aim > g.hardest {
+		aim = g.hardest
+	}
+	min, max := aim.pressRange(most)
+
+	var closest *Puzzle
+	for attempt := 0; attempt < g.attempts; attempt++ {
+		b, _ := board.NewWithRules(width, height, rules)
+		for _, i := range rng.Perm(most)[:rng.IntRange(min, max)] {
+			i %= cells
+			b.Toggle(i%width, i/width)
+		}
+		if b.IsSolved() {
+			continue
+		}
+		solution, err := g.system.Solve(b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to solve generated puzzle: %v", err)
+		}
+		p := &Puzzle{Seed: seed, Board: b, Solution: solution, Target: d, Difficulty: Rate(solution.Count(), most)}
+		if p.Difficulty == aim {
+			return p, nil
+		}
+		if closest == nil || abs(int(p.Difficulty-aim)) < abs(int(closest.Difficulty-aim)) {
+			closest = p
+		}
+	}
+	// the difficulty wasn't reached, settle for the nearest
+	if closest == nil {
+		b, _ := board.NewWithRules(width, height, rules)
+		b.Toggle(rng.Intn(width), rng.Intn(height))
+		solution, err := g.system.Solve(b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to solve generated puzzle: %v", err)
+		}
+		closest = &Puzzle{Seed: seed, Board: b, Solution: solution, Target: d, Difficulty: Rate(solution.Count(), most)}
+	}
+	return closest, nil
+}
+
+// Random makes a puzzle from a clock seed.
+func (g *Generator) Random(d Difficulty) (*Puzzle, error) {
+	return g.Generate(rng.RandomSeed(), d)
+}
+
+// DailySeed returns the seed shared by everyone playing on the day of t, in
+// UTC.
+func DailySeed(t time.Time) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(t.UTC().Format("2006-01-02")))
+	return int64(h.Sum64() >> 1)
+}
+
+// Daily makes the puzzle of the day of t.
+func (g *Generator) Daily(t time.Time, d Difficulty) (*Puzzle, error) {
+	return g.Generate(DailySeed(t), d)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/lightsout/board"
+)
+
+const (
+	replayStepSeconds = 0.75
+	replayFadeSeconds = 0.3
+)
+
+var _ games.Updater = &replay{}
+
+// replay plays a game's moves back from its starting board, fading each
+// press in.
+type replay struct {
+	start *board.Board
+	moves []board.Move
+	// before and after are the boards either side of the move shown.
+	before, after *board.Board
+	// step is the number of moves applied to after.
+	step   int
+	clock  float64
+	paused bool
+}
+
+func newReplay(start *board.Board, moves []board.Move) *replay {
+	return &replay{
+		start:  start.Clone(),
+		moves:  moves,
+		before: start.Clone(),
+		after:  start.Clone(),
+		// nothing to fade in yet
+		clock: replayFadeSeconds,
+	}
+}
+
+func (r *replay) Update(dt float64) {
+	if r.paused {
+		return
+	}
+	r.clock += dt
+	if r.clock >= replayStepSeconds {
+		r.forward()
+	}
+}
+
+// forward shows the next move.
+func (r *replay) forward() bool {
+	if r.step == len(r.moves) {
+		return false
+	}
+	r.before = r.after.Clone()
+	m := r.moves[r.step]
+	r.after.Press(m.X, m.Y)
+	r.step++
+	r.clock = 0
+	return true
+}
+
+// back shows the previous move again, without fading.
+func (r *replay) back() bool {
+	if r.step == 0 {
+		return false
+	}
+	r.step--
+	r.after = r.start.Clone()
+	for _, m := range r.moves[:r.step] {
+		r.after.Press(m.X, m.Y)
+	}
+	r.before = r.after.Clone()
+	r.clock = replayFadeSeconds
+	return true
+}
+
+// fade returns how far the latest move has faded in, from 0 to 1.
+func (r *replay) fade() float64 {
+	return games.LimitWithinBounds(r.clock/replayFadeSeconds, 0, 1)
+}
+
+// move returns the last move shown.
+func (r *replay) move() (board.Move, bool) {
+	if r.step == 0 {
+		return board.Move{}, false
+	}
+	return r.moves[r.step-1], true
+}
+
+func (r *replay) done() bool {
+	return r.step == len(r.moves) && r.clock >= replayFadeSeconds
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/game"
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/explodes/gogames/persistence"
+	"github.com/faiface/pixel"
+)
+
+const (
+	saveVersion  = 4
+	autosaveSlot = "autosave"
+
+	progressVersion = 1
+	progressSlot    = "progress"
+)
+
+type savedGame struct {
+	Puzzle  string  `json:"puzzle,omitempty"`
+	Level   string  `json:"level,omitempty"`
+	Rules   string  `json:"rules"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	States  []int   `json:"states"`
+	Moves   int     `json:"moves"`
+	Elapsed float64 `json:"elapsed"`
+	// Start and History let moves be undone and replayed. Cursor is the
+	// number of History moves applied.
+	Start   []int        `json:"start"`
+	History []board.Move `json:"history"`
+	Cursor  int          `json:"cursor"`
+}
+
+func newSaveStore() (*persistence.Store, error) {
+	store, err := persistence.NewStore("lightsout", saveVersion, persistence.JSON)
+	if err != nil {
+		return nil, err
+	}
+	// version 1 only saved square grids and didn't record their size
+	store.Migrate(1, persistence.JSONMigration(func(state map[string]interface{}) error {
+		squares, _ := state["squares"].([]interface{})
+		side := int(math.Sqrt(float64(len(squares))))
+		if side*side != len(squares) {
+			return fmt.Errorf("%d squares is not a square grid", len(squares))
+		}
+		state["width"] = side
+		state["height"] = side
+		return nil
+	}))
+	// version 2 only had classic on and off lights
+	store.Migrate(2, persistence.JSONMigration(func(state map[string]interface{}) error {
+		squares, _ := state["squares"].([]interface{})
+		states := make([]int, len(squares))
+		for i, on := range squares {
+			if on == true {
+				states[i] = 1
+			}
+		}
+		delete(state, "squares")
+		state["states"] = states
+		state["rules"] = board.Classic.String()
+		return nil
+	}))
+	// version 3 had no history, so older moves can't be undone
+	store.Migrate(3, persistence.JSONMigration(func(state map[string]interface{}) error {
+		state["start"] = state["states"]
+		state["history"] = []interface{}{}
+		state["cursor"] = 0
+		return nil
+	}))
+	return store, nil
+}
+
+// newProgressStore opens the store of solved levels, kept apart from the
+// autosave so that neither format's versions hold back the other.
+func newProgressStore() (*persistence.Store, error) {
+	dir, err := persistence.Dir("lightsout")
+	if err != nil {
+		return nil, err
+	}
+	return persistence.NewStoreInDir(filepath.Join(dir, "progress"), progressVersion, persistence.JSON), nil
+}
+
+func saveGame(store *persistence.Store, grid *Grid, elapsed float64) error {
+	level := ""
+	if grid.Level != nil {
+		level = grid.Level.Key()
+	}
+	return store.Save(autosaveSlot, savedGame{
+		Puzzle:  grid.Code,
+		Level:   level,
+		Rules:   grid.Board.Rules().String(),
+		Width:   grid.Board.Width(),
+		Height:  grid.Board.Height(),
+		States:  grid.Board.States(),
+		Moves:   grid.Board.Moves(),
+		Elapsed: elapsed,
+		Start:   grid.Start.States(),
+		History: grid.History.Moves(),
+		Cursor:  grid.History.Cursor(),
+	})
+}
+
+// loadGame restores the autosave. The caller checks that a generated puzzle
+// matches the configured size and rules; saved levels are found in packs.
+func loadGame(store *persistence.Store, bounds pixel.Rect, packs []*levels.Pack) (*Grid, float64, error) {
+	var saved savedGame
+	if err := store.Load(autosaveSlot, &saved); err != nil {
+		return nil, 0, err
+	}
+	rules, err := board.ParseRules(saved.Rules)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := board.FromStates(saved.Width, saved.Height, rules, saved.States)
+	if err != nil {
+		return nil, 0, err
+	}
+	b.SetMoves(saved.Moves)
+	start, err := board.FromStates(saved.Width, saved.Height, rules, saved.Start)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid starting board: %v", err)
+	}
+	history := board.NewHistory(saved.History)
+	if err := history.SetCursor(saved.Cursor); err != nil {
+		return nil, 0, err
+	}
+
+	g := game.New(b, saved.Puzzle)
+	g.Start = start
+	g.History = history
+	if saved.Level != "" {
+		if g.Level = levels.Find(packs, saved.Level); g.Level == nil {
+			return nil, 0, fmt.Errorf("saved level %s is not in any pack", saved.Level)
+		}
+	}
+	return newGrid(g, bounds), saved.Elapsed, nil
+}
@@ -0,0 +1,370 @@
+package solver
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/lightsout/board"
+)
+
+// maxWork bounds the search of the null space for a minimal solution: the
+// combinations searched times the words in each vector, about a tenth of a
+// second.
+const maxWork = 1 << 24
+
+// System is the press matrix of one board size and set of rules, already
+// reduced so that solving any arrangement of lights is a matrix-vector
+// product. Lights with k states are solved modulo k, which must be prime.
+//
+// Pressing cells x[j] times each turns lights b off exactly when A x = -b
+// modulo k, where column j of A counts how often pressing cell j advances
+// each light. Presses commute and pressing a cell k times does nothing, so x
+// is just how often to press each cell.
+type System struct {
+	width, height int
+	rules         board.Rules
+	n             int
+	modulus       int
+	// reduced is A in reduced row echelon form, and ops the row operations
+	// that got it there, so ops A = reduced.
+	reduced []vector
+	ops     []vector
+	pivots  []int
+	free    []int
+	null    []vector
+}
+
+// NewSystem builds and reduces the press matrix for width by height boards
+// played with rules.
+func NewSystem(width, height int, rules board.Rules) (*System, error) {
+	if err := Supports(rules); err != nil {
+		return nil, err
+	}
+	b, err := board.NewWithRules(width, height, rules)
+	if err != nil {
+		return nil, err
+	}
+	n := width * height
+	s := &System{
+		width:   width,
+		height:  height,
+		rules:   rules,
+		n:       n,
+		modulus: rules.States,
+		reduced: make([]vector, n),
+		ops:     make([]vector, n),
+	}
+	for i := 0; i < n; i++ {
+		s.reduced[i] = newVector(n, s.modulus)
+		s.ops[i] = newVector(n, s.modulus)
+		s.ops[i].set(i, 1)
+	}
+	for j := 0; j < n; j++ {
+		for _, cell := range b.Toggled(j%width, j/width) {
+			i := b.Index(cell[0], cell[1])
+			s.reduced[i].set(j, s.reduced[i].get(j)+1)
+		}
+	}
+	s.reduce()
+	s.nullSpace()
+	return s, nil
+}
+
+// Supports checks that boards played with rules can be solved.
+func Supports(rules board.Rules) error {
+	if !isPrime(rules.States) {
+		return fmt.Errorf("can only solve lights with a prime number of states, not %d", rules.States)
+	}
+	return nil
+}
+
+// reduce runs Gauss-Jordan elimination, recording pivot columns.
+func (s *System) reduce() {
+	isPivot := make([]bool, s.n)
+	row := 0
+	for col := 0; col < s.n && row < s.n; col++ {
+		pivot := -1
+		for r := row; r < s.n; r++ {
+			if s.reduced[r].get(col) != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		s.reduced[row], s.reduced[pivot] = s.reduced[pivot], s.reduced[row]
+		s.ops[row], s.ops[pivot] = s.ops[pivot], s.ops[row]
+		inv := inverse(s.reduced[row].get(col), s.modulus)
+		s.reduced[row].scale(inv)
+		s.ops[row].scale(inv)
+		for r := 0; r < s.n; r++ {
+			if v := s.reduced[r].get(col); r != row && v != 0 {
+				s.reduced[r].addScaled(s.reduced[row], -v)
+				s.ops[r].addScaled(s.ops[row], -v)
+			}
+		}
+		s.pivots = append(s.pivots, col)
+		isPivot[col] = true
+		row++
+	}
+	for col := 0; col < s.n; col++ {
+		if !isPivot[col] {
+			s.free = append(s.free, col)
+		}
+	}
+}
+
+// nullSpace finds a basis of the presses that change nothing, one per free
+// column.
+func (s *System) nullSpace() {
+	for _, f := range s.free {
+		v := newVector(s.n, s.modulus)
+		v.set(f, 1)
+		for r, p := range s.pivots {
+			v.set(p, -s.reduced[r].get(f))
+		}
+		s.null = append(s.null, v)
+	}
+}
+
+func (s *System) Width() int {
+	return s.width
+}
+
+func (s *System) Height() int {
+	return s.height
+}
+
+func (s *System) Rules() board.Rules {
+	return s.rules
+}
+
+// Rank returns the rank of the press matrix.
+func (s *System) Rank() int {
+	return len(s.pivots)
+}
+
+// Nullity returns the dimension of the null space. Every solvable board has
+// k^Nullity solutions, and only 1 in k^Nullity boards is solvable.
+func (s *System) Nullity() int {
+	return len(s.null)
+}
+
+// NullSpace returns a basis of the presses that leave every light as it
+// was, as press counts in row order starting at the bottom row.
+func (s *System) NullSpace() [][]int {
+	basis := make([][]int, len(s.null))
+	for i, v := range s.null {
+		basis[i] = ints(v, s.n)
+	}
+	return basis
+}
+
+// Unsolvable is returned for boards that can't be turned off. It carries
+// the proof: weighting each light's state by Witness, every press changes
+// the weighted sum by a multiple of the number of states, so the sum modulo
+// the number of states never changes, and it isn't 0.
+type Unsolvable struct {
+	Width, Height int
+	Witness       []int
+}
+
+func (u *Unsolvable) Error() string {
+	used := 0
+	for _, w := range u.Witness {
+		if w != 0 {
+			used++
+		}
+	}
+	return fmt.Sprintf("unsolvable: a weighted sum of %d lights can't change and isn't 0", used)
+}
+
+// Solution says how many times to press each cell, in any order.
+type Solution struct {
+	Width, Height int
+	Presses       []int
+	// Optimal is true when no solution has fewer presses. It is false only
+	// when the null space was too large to search.
+	Optimal bool
+}
+
+// Count returns the total number of presses.
+func (s *Solution) Count() int {
+	n := 0
+	for _, presses := range s.Presses {
+		n += presses
+	}
+	return n
+}
+
+// Press returns how many times to press the cell at (x, y).
+func (s *Solution) Press(x, y int) int {
+	if x < 0 || x >= s.Width || y < 0 || y >= s.Height {
+		return 0
+	}
+	return s.Presses[x+y*s.Width]
+}
+
+// Next returns a cell to press, the first in row order from the top, or
+// false when nothing needs pressing.
+func (s *Solution) Next() (x, y int, ok bool) {
+	for y := s.Height - 1; y >= 0; y-- {
+		for x := 0; x < s.Width; x++ {
+			if s.Press(x, y) > 0 {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Apply makes every press of the solution on b.
+func (s *Solution) Apply(b *board.Board) {
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			for i := s.Press(x, y); i > 0; i-- {
+				b.Press(x, y)
+			}
+		}
+	}
+}
+
+// Solve returns a solution for b with as few presses as possible, or an
+// *Unsolvable error.
+func (s *System) Solve(b *board.Board) (*Solution, error) {
+	if err := s.check(b); err != nil {
+		return nil, err
+	}
+	target := s.target(b)
+	if witness := s.witness(target); witness != nil {
+		return nil, &Unsolvable{Width: s.width, Height: s.height, Witness: ints(witness, s.n)}
+	}
+
+	x := newVector(s.n, s.modulus)
+	for r, p := range s.pivots {
+		x.set(p, s.ops[r].dot(target))
+	}
+	x, optimal := s.minimize(x)
+	return &Solution{Width: s.width, Height: s.height, Presses: ints(x, s.n), Optimal: optimal}, nil
+}
+
+func (s *System) check(b *board.Board) error {
+	if b.Width() != s.width || b.Height() != s.height || b.Rules().String() != s.rules.String() {
+		return fmt.Errorf("board is %dx%d %s, solver is for %dx%d %s", b.Width(), b.Height(), b.Rules(), s.width, s.height, s.rules)
+	}
+	return nil
+}
+
+// target is how far each light has to advance to get back to 0.
+func (s *System) target(b *board.Board) vector {
+	states := b.States()
+	for i, state := range states {
+		states[i] = -state
+	}
+	return vectorOf(states, s.modulus)
+}
+
+// witness returns a row of ops that proves target unreachable, or nil.
+// Where reduced has an all zero row, ops times target must be zero too.
+func (s *System) witness(target vector) vector {
+	for r := len(s.pivots); r < s.n; r++ {
+		if s.ops[r].dot(target) != 0 {
+			return s.ops[r]
+		}
+	}
+	return nil
+}
+
+// search returns how many combinations of the null space minimize goes
+// through and the work that takes, or false when there are too many and it
+// improves greedily instead.
+func (s *System) search() (combinations, work int, exhaustive bool) {
+	size := newVector(s.n, s.modulus).size()
+	combinations = 1
+	for range s.null {
+		combinations *= s.modulus
+		if combinations*size > maxWork {
+			return 0, len(s.null) * s.modulus * size, false
+		}
+	}
+	return combinations, combinations * size, true
+}
+
+// SearchWork estimates the work of a Solve beyond the elimination: the
+// combinations of the null space searched times the words in each vector.
+// It is at most 1<<24.
+func (s *System) SearchWork() int {
+	_, work, _ := s.search()
+	return work
+}
+
+// minimize searches x plus every combination of the null space for the
+// fewest presses. The combinations are counted through like an odometer,
+// adding a basis vector for each digit that turns; a digit wrapping back to
+// 0 has added its vector k times, which is nothing. Null spaces too big to
+// search are improved greedily instead.
+func (s *System) minimize(x vector) (vector, bool) {
+	if len(s.null) == 0 {
+		return x, true
+	}
+	combinations, _, exhaustive := s.search()
+	if !exhaustive {
+		return s.improve(x), false
+	}
+	best, bestCount := x.clone(), x.sum()
+	current := x.clone()
+	digits := make([]int, len(s.null))
+	for i := 1; i < combinations; i++ {
+		for d := range digits {
+			current.addScaled(s.null[d], 1)
+			digits[d]++
+			if digits[d] < s.modulus {
+				break
+			}
+			digits[d] = 0
+		}
+		if c := current.sum(); c < bestCount {
+			best, bestCount = current.clone(), c
+		}
+	}
+	return best, true
+}
+
+// improve adds multiples of null space vectors to x while that removes
+// presses.
+func (s *System) improve(x vector) vector {
+	count := x.sum()
+	for improved := true; improved; {
+		improved = false
+		for _, v := range s.null {
+			for c := 1; c < s.modulus; c++ {
+				x.addScaled(v, c)
+				if n := x.sum(); n < count {
+					count = n
+					improved = true
+					break
+				}
+				x.addScaled(v, -c)
+			}
+		}
+	}
+	return x
+}
+
+// Solve builds a System for b's size and rules and solves b. Reuse a System
+// to solve many boards of the same kind.
+func Solve(b *board.Board) (*Solution, error) {
+	s, err := NewSystem(b.Width(), b.Height(), b.Rules())
+	if err != nil {
+		return nil, err
+	}
+	return s.Solve(b)
+}
+
+// Solvable reports whether b can be turned off.
+func (s *System) Solvable(b *board.Board) bool {
+	if s.check(b) != nil {
+		return false
+	}
+	return s.witness(s.target(b)) == nil
+}
@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/rng"
+)
+
+func newSystem(t *testing.T, width, height int, variant string) *System {
+	t.Helper()
+	rules, err := board.ParseRules(variant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSystem(width, height, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// scrambled presses random cells of a solved board, so it is always solvable
+// in at most presses presses.
+func scrambled(s *System, seed int64, presses int) *board.Board {
+	b, _ := board.NewWithRules(s.Width(), s.Height(), s.Rules())
+	r := rng.New(seed)
+	for i := 0; i < presses; i++ {
+		b.Press(r.Intn(s.Width()), r.Intn(s.Height()))
+	}
+	return b
+}
+
+func checkSolution(t *testing.T, b *board.Board, solution *Solution) {
+	t.Helper()
+	b = b.Clone()
+	solution.Apply(b)
+	if !b.IsSolved() {
+		t.Errorf("solution with %d presses leaves lights on", solution.Count())
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		variant       string
+		nullity       int
+		// presses is the shortest solution for the all lit board, or 0 when
+		// it is unsolvable
+		presses int
+	}{
+		{"classic 3x3", 3, 3, "classic", 0, 5},
+		{"classic 4x4", 4, 4, "classic", 4, 4},
+		{"classic 5x5", 5, 5, "classic", 2, 15},
+		{"classic 6x6", 6, 6, "classic", 0, 28},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newSystem(t, test.width, test.height, test.variant)
+			if s.Nullity() != test.nullity {
+				t.Errorf("nullity %d, want %d", s.Nullity(), test.nullity)
+			}
+
+			lit := board.NewLit(test.width, test.height)
+			solution, err := s.Solve(lit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !solution.Optimal {
+				t.Error("small board solution isn't optimal")
+			}
+			if solution.Count() != test.presses {
+				t.Errorf("solved the lit board in %d presses, want %d", solution.Count(), test.presses)
+			}
+			checkSolution(t, lit, solution)
+		})
+	}
+}
+
+func TestUnsolvable(t *testing.T) {
+	s := newSystem(t, 5, 5, "classic")
+	b := board.New(5, 5)
+	b.Set(0, 0, true)
+	if s.Solvable(b) {
+		t.Fatal("a single corner light on 5x5 is reported solvable")
+	}
+	_, err := s.Solve(b)
+	var unsolvable *Unsolvable
+	if !errors.As(err, &unsolvable) {
+		t.Fatalf("got error %v, want *Unsolvable", err)
+	}
+
+	// the witness weights must sum to a nonzero value over the lit lights
+	// and to zero over every press
+	if unsolvable.Witness[b.Index(0, 0)] == 0 {
+		t.Error("witness ignores the only lit light")
+	}
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			sum := 0
+			for _, cell := range b.Toggled(x, y) {
+				sum += unsolvable.Witness[b.Index(cell[0], cell[1])]
+			}
+			if sum%2 != 0 {
+				t.Errorf("pressing (%d, %d) changes the witness sum", x, y)
+			}
+		}
+	}
+}
+
+func TestSolveBeyondSearch(t *testing.T) {
+	s := newSystem(t, 12, 12, "square+torus")
+	if _, _, exhaustive := s.search(); exhaustive {
+		t.Fatalf("nullity %d is small enough to search, pick a bigger board", s.Nullity())
+	}
+	if work := s.SearchWork(); work > maxWork {
+		t.Errorf("search work %d is over the %d bound", work, maxWork)
+	}
+	b := scrambled(s, 1, 40)
+	solution, err := s.Solve(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution.Optimal {
+		t.Error("solution claims to be optimal without a full search")
+	}
+	checkSolution(t, b, solution)
+}
@@ -0,0 +1,181 @@
+package solver
+
+import "math/bits"
+
+// vector is a row of the press matrix, or a set of presses, with one value
+// per light modulo the number of light states.
+type vector interface {
+	get(i int) int
+	set(i, value int)
+	// addScaled adds c times o.
+	addScaled(o vector, c int)
+	// scale multiplies every value by c.
+	scale(c int)
+	dot(o vector) int
+	// sum is the total of every value, the number of presses.
+	sum() int
+	clone() vector
+	// size is the number of words each operation works through.
+	size() int
+}
+
+func newVector(n, modulus int) vector {
+	if modulus == 2 {
+		return make(bitVector, (n+63)/64)
+	}
+	return &modVector{values: make([]uint8, n), modulus: modulus}
+}
+
+func vectorOf(values []int, modulus int) vector {
+	v := newVector(len(values), modulus)
+	for i, value := range values {
+		v.set(i, value)
+	}
+	return v
+}
+
+func ints(v vector, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = v.get(i)
+	}
+	return values
+}
+
+// bitVector packs values modulo 2 into words, so that adding rows is xor.
+type bitVector []uint64
+
+func (v bitVector) get(i int) int {
+	return int(v[i/64]>>uint(i%64)) & 1
+}
+
+func (v bitVector) set(i, value int) {
+	if value&1 == 1 {
+		v[i/64] |= 1 << uint(i%64)
+	} else {
+		v[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+func (v bitVector) addScaled(o vector, c int) {
+	if c&1 == 0 {
+		return
+	}
+	ob := o.(bitVector)
+	for i := range v {
+		v[i] ^= ob[i]
+	}
+}
+
+func (v bitVector) scale(c int) {
+	if c&1 == 0 {
+		for i := range v {
+			v[i] = 0
+		}
+	}
+}
+
+func (v bitVector) dot(o vector) int {
+	ob := o.(bitVector)
+	n := 0
+	for i := range v {
+		n += bits.OnesCount64(v[i] & ob[i])
+	}
+	return n & 1
+}
+
+func (v bitVector) sum() int {
+	n := 0
+	for _, w := range v {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (v bitVector) size() int {
+	return len(v)
+}
+
+func (v bitVector) clone() vector {
+	return append(bitVector(nil), v...)
+}
+
+// modVector holds values modulo a prime above 2.
+type modVector struct {
+	values  []uint8
+	modulus int
+}
+
+func (v *modVector) get(i int) int {
+	return int(v.values[i])
+}
+
+func (v *modVector) set(i, value int) {
+	v.values[i] = uint8((value%v.modulus + v.modulus) % v.modulus)
+}
+
+func (v *modVector) addScaled(o vector, c int) {
+	c = (c%v.modulus + v.modulus) % v.modulus
+	if c == 0 {
+		return
+	}
+	for i, value := range o.(*modVector).values {
+		if value != 0 {
+			v.values[i] = uint8((int(v.values[i]) + c*int(value)) % v.modulus)
+		}
+	}
+}
+
+func (v *modVector) scale(c int) {
+	c = (c%v.modulus + v.modulus) % v.modulus
+	for i, value := range v.values {
+		v.values[i] = uint8(c * int(value) % v.modulus)
+	}
+}
+
+func (v *modVector) dot(o vector) int {
+	n := 0
+	for i, value := range o.(*modVector).values {
+		n += int(v.values[i]) * int(value)
+	}
+	return n % v.modulus
+}
+
+func (v *modVector) sum() int {
+	n := 0
+	for _, value := range v.values {
+		n += int(value)
+	}
+	return n
+}
+
+func (v *modVector) size() int {
+	return len(v.values)
+}
+
+func (v *modVector) clone() vector {
+	return &modVector{values: append([]uint8(nil), v.values...), modulus: v.modulus}
+}
+
+// inverse returns the multiplicative inverse of a modulo the prime p.
+func inverse(a, p int) int {
+	a = (a%p + p) % p
+	for b := 1; b < p; b++ {
+		if a*b%p == 1 {
+			return b
+		}
+	}
+	return 0
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/lightsout/solver"
+)
+
+type Config struct {
+	GridSideLength int    `json:"grid_side_length" help:"number of squares along each side of the grid" min:"2" max:"32"`
+	Variant        string `json:"variant" help:"rules: classic, x, square, hex or a mask such as mask:.#.,###,.#.; add +torus to wrap the edges and +k3 for lights with 3 states"`
+	Difficulty     string `json:"difficulty" help:"puzzle difficulty: easy, medium, hard or expert"`
+	Seed           int64  `json:"seed" help:"random seed, picked from the clock when 0"`
+	Daily          bool   `json:"daily" help:"play today's puzzle, the same for everyone"`
+	Puzzle         string `json:"puzzle" help:"play a shared puzzle code such as 8x8-hard-12345"`
+	Level          string `json:"level" help:"play a level such as starter/1"`
+	Levels         string `json:"levels" help:"directory of extra level packs, each a .json file"`
+	Headless       bool   `json:"headless" help:"read keys from stdin without a terminal and print only the final board; exits 1 when unsolved"`
+	Color          bool   `json:"color" help:"draw lights with 24-bit colors"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		GridSideLength: 8,
+		Variant:        "classic",
+		Difficulty:     "medium",
+		Color:          true,
+	}
+}
+
+func (c Config) Validate() error {
+	if _, err := puzzle.ParseDifficulty(c.Difficulty); err != nil {
+		return err
+	}
+	rules, err := board.ParseRules(c.Variant)
+	if err != nil {
+		return err
+	}
+	width, height := c.GridSideLength, c.GridSideLength
+	if c.Puzzle != "" {
+		spec, err := puzzle.ParseCode(c.Puzzle)
+		if err != nil {
+			return err
+		}
+		if spec.Width < 2 || spec.Width > 32 || spec.Height < 2 || spec.Height > 32 {
+			return fmt.Errorf("puzzle %q is not a grid of 2 to 32 squares", c.Puzzle)
+		}
+		rules, width, height = spec.Rules, spec.Width, spec.Height
+	}
+	if err := rules.Validate(width, height); err != nil {
+		return err
+	}
+	// puzzles are generated and rated with the solver
+	return solver.Supports(rules)
+}
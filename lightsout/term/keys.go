@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+)
+
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPress
+	keyUndo
+	keyRedo
+	keyHint
+	keyRestart
+	keyNew
+	keyQuit
+)
+
+// readKey reads one key from r, decoding the escape sequences sent for the
+// arrow keys. Unknown keys are keyNone.
+func readKey(r *bufio.Reader) (key, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return keyNone, err
+	}
+	switch c {
+	case 0x1b:
+		return readEscape(r)
+	case 'k', 'w':
+		return keyUp, nil
+	case 'j', 's':
+		return keyDown, nil
+	case 'h', 'a':
+		return keyLeft, nil
+	case 'l', 'd':
+		return keyRight, nil
+	case '\r', '\n', ' ':
+		return keyPress, nil
+	case 'u', 'z':
+		return keyUndo, nil
+	case 'y':
+		return keyRedo, nil
+	case '?':
+		return keyHint, nil
+	case 'r':
+		return keyRestart, nil
+	case 'n':
+		return keyNew, nil
+	case 'q', 0x03, 0x04:
+		// ctrl-c and ctrl-d do not raise signals in raw mode
+		return keyQuit, nil
+	}
+	return keyNone, nil
+}
+
+// readEscape decodes the rest of an escape sequence: ESC [ A for the up arrow
+// from most terminals, ESC O A in application cursor mode. A lone escape
+// quits.
+func readEscape(r *bufio.Reader) (key, error) {
+	if r.Buffered() == 0 {
+		return keyQuit, nil
+	}
+	c, err := r.ReadByte()
+	if err != nil {
+		return keyNone, err
+	}
+	if c != '[' && c != 'O' {
+		return keyNone, nil
+	}
+	// skip parameters such as the 1;5 of ctrl-arrow
+	for {
+		if c, err = r.ReadByte(); err != nil {
+			return keyNone, err
+		}
+		if (c < '0' || c > '9') && c != ';' {
+			break
+		}
+	}
+	switch c {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'C':
+		return keyRight, nil
+	case 'D':
+		return keyLeft, nil
+	}
+	return keyNone, nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/explodes/gogames/lightsout/game"
+	"github.com/explodes/gogames/lightsout/levels"
+	"github.com/explodes/gogames/lightsout/puzzle"
+	"github.com/explodes/gogames/rng"
+)
+
+const title = "Lights Out"
+
+// session is the game being played and the cursor over its board.
+type session struct {
+	*game.Game
+	cursorX, cursorY int
+	hint             mark
+	status           string
+
+	generator  *puzzle.Generator
+	difficulty puzzle.Difficulty
+	// seeds are for the games after the first, so a seed replays a whole
+	// session
+	seeds *rng.RNG
+
+	render  *renderer
+	color   bool
+	newline string
+}
+
+func (s *session) play(g *game.Game) {
+	s.Game = g
+	s.cursorX, s.cursorY = 0, g.Board.Height()-1
+	s.hint.shown = false
+	s.status = ""
+	s.render = newRenderer(g.Board, s.color, s.newline)
+}
+
+// handle applies a key and reports whether to keep playing.
+func (s *session) handle(k key) bool {
+	b := s.Board
+	solved := s.Solved()
+	switch k {
+	case keyQuit:
+		return false
+	case keyUp:
+		s.cursorY = (s.cursorY + 1) % b.Height()
+	case keyDown:
+		s.cursorY = (s.cursorY + b.Height() - 1) % b.Height()
+	case keyLeft:
+		s.cursorX = (s.cursorX + b.Width() - 1) % b.Width()
+	case keyRight:
+		s.cursorX = (s.cursorX + 1) % b.Width()
+	case keyPress:
+		if !solved && s.Press(s.cursorX, s.cursorY) {
+			s.hint.shown = false
+			s.status = ""
+		}
+	case keyUndo:
+		if !solved && s.Undo() {
+			s.hint.shown = false
+			s.status = ""
+		}
+	case keyRedo:
+		if !solved && s.Redo() {
+			s.hint.shown = false
+			s.status = ""
+		}
+	case keyHint:
+		if solved {
+			break
+		}
+		s.hint.shown = false
+		solution, err := s.Solve()
+		if err != nil {
+			s.status = "hint: unsolvable"
+		} else if x, y, ok := solution.Next(); ok {
+			s.hint = mark{x: x, y: y, shown: true, left: '<', right: '>'}
+			s.status = fmt.Sprintf("hint: %d presses left", solution.Count())
+		}
+	case keyRestart:
+		s.play(s.Restart())
+	case keyNew:
+		switch {
+		case s.Level == nil:
+			p, err := s.generator.Generate(s.seeds.Int63(), s.difficulty)
+			if err != nil {
+				s.status = fmt.Sprintf("unable to create a puzzle: %v", err)
+				break
+			}
+			s.play(game.FromPuzzle(p))
+		case solved && s.Level.Next() != nil:
+			s.play(game.FromLevel(s.Level.Next()))
+		}
+	}
+	return true
+}
+
+// draw writes the whole screen.
+func (s *session) draw(w io.Writer) {
+	var sb strings.Builder
+	r := s.render
+	sb.WriteString(clearScreen)
+	r.line(&sb, "%s | %s", title, s.Label())
+	r.line(&sb, "")
+	r.board(&sb, s.Board, mark{x: s.cursorX, y: s.cursorY, shown: true, left: '[', right: ']'}, s.hint)
+	r.line(&sb, "")
+	switch {
+	case s.Solved() && s.Level != nil:
+		l := s.Level
+		r.line(&sb, "solved in %d moves, par %d: %s", s.Moves(), l.Par, strings.Repeat("*", levels.Stars(s.Moves(), l.Par)))
+		if l.Next() != nil {
+			r.line(&sb, "n next level, r retry, q quit")
+		} else {
+			r.line(&sb, "r retry, q quit")
+		}
+	case s.Solved():
+		r.line(&sb, "solved in %d moves", s.Moves())
+		r.line(&sb, "n new puzzle, r retry, q quit")
+	default:
+		if s.status != "" {
+			r.line(&sb, "moves: %d | %s", s.Moves(), s.status)
+		} else {
+			r.line(&sb, "moves: %d", s.Moves())
+		}
+		r.line(&sb, "arrows/hjkl move, enter press, u undo, y redo, ? hint, r restart, n new, q quit")
+	}
+	io.WriteString(w, sb.String())
+}
+
+func main() {
+	cfg := defaultConfig()
+	if err := config.Load("lightsout-term", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "unable to load config")
+	}
+	headless := cfg.Headless || !isTerminal(os.Stdin)
+
+	s := &session{color: cfg.Color && !headless, newline: "\n"}
+	first, err := setup(cfg, s)
+	if err != nil {
+		exitWith(err, "unable to start")
+	}
+
+	if headless {
+		os.Exit(runHeadless(s, first, os.Stdin, os.Stdout))
+	}
+	if err := runTerminal(s, first); err != nil {
+		exitWith(err, "unable to play")
+	}
+}
+
+// setup prepares s for the configured puzzles and returns the first game.
+func setup(cfg Config, s *session) (*game.Game, error) {
+	size := cfg.GridSideLength
+	width, height := size, size
+	rules, _ := board.ParseRules(cfg.Variant)
+	difficulty, _ := puzzle.ParseDifficulty(cfg.Difficulty)
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	switch {
+	case cfg.Puzzle != "":
+		spec, _ := puzzle.ParseCode(cfg.Puzzle)
+		width, height, rules, difficulty, seed = spec.Width, spec.Height, spec.Rules, spec.Difficulty, spec.Seed
+	case cfg.Daily:
+		seed = puzzle.DailySeed(time.Now())
+	}
+	generator, err := puzzle.NewGenerator(width, height, rules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create puzzles: %v", err)
+	}
+	s.generator, s.difficulty, s.seeds = generator, difficulty, rng.New(seed)
+
+	if cfg.Level == "" {
+		p, err := generator.Generate(seed, difficulty)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a puzzle: %v", err)
+		}
+		return game.FromPuzzle(p), nil
+	}
+	packs, err := levels.Builtin()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load built in levels: %v", err)
+	}
+	if cfg.Levels != "" {
+		extra, err := levels.LoadDir(cfg.Levels)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load levels from %s: %v", cfg.Levels, err)
+		}
+		packs = levels.Merge(packs, extra)
+	}
+	l := levels.Find(packs, cfg.Level)
+	if l == nil {
+		return nil, fmt.Errorf("no level %s", cfg.Level)
+	}
+	return game.FromLevel(l), nil
+}
+
+// runTerminal plays interactively until the player quits.
+func runTerminal(s *session, first *game.Game) error {
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	s.newline = "\r\n"
+	s.play(first)
+
+	fmt.Print(hideCursor)
+	defer fmt.Print(showCursor, resetStyle)
+	in := bufio.NewReader(os.Stdin)
+	for {
+		s.draw(os.Stdout)
+		k, err := readKey(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !s.handle(k) {
+			fmt.Print(s.newline)
+			return nil
+		}
+	}
+}
+
+// runHeadless plays the keys read from in without drawing anything until they
+// run out, then prints the board and returns the exit code: 0 when it is
+// solved and 1 when not.
+func runHeadless(s *session, first *game.Game, in io.Reader, out io.Writer) int {
+	s.play(first)
+	keys := bufio.NewReader(in)
+	for {
+		k, err := readKey(keys)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(out, "unable to read keys: %v\n", err)
+			return 1
+		}
+		if !s.handle(k) {
+			break
+		}
+	}
+	fmt.Fprintln(out, s.Label())
+	fmt.Fprint(out, s.Board)
+	if !s.Solved() {
+		fmt.Fprintf(out, "unsolved after %d moves, %d lights on\n", s.Moves(), s.Board.Lit())
+		return 1
+	}
+	fmt.Fprintf(out, "solved in %d moves\n", s.Moves())
+	return 0
+}
+
+func exitWith(err error, msg string, args ...interface{}) {
+	fmt.Printf("%s: %v\n", fmt.Sprintf(msg, args...), err)
+	os.Exit(2)
+}
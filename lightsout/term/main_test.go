@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestHeadless(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		keys  string
+		code  int
+		board string
+		last  string
+	}{
+		{
+			name:  "solved",
+			level: "starter/1",
+			// down and right from the top left corner, then press
+			keys:  "jl\n",
+			board: "...\n...\n...\n",
+			last:  "solved in 1 moves",
+		},
+		{
+			name:  "arrow keys",
+			level: "starter/1",
+			keys:  "\x1b[B\x1bOC ",
+			board: "...\n...\n...\n",
+			last:  "solved in 1 moves",
+		},
+		{
+			name:  "undone",
+			level: "starter/1",
+			// press the top left corner and take it back
+			keys:  " u",
+			code:  1,
+			board: ".#.\n###\n.#.\n",
+			last:  "unsolved after 0 moves, 5 lights on",
+		},
+		{
+			name:  "redone",
+			level: "starter/1",
+			keys:  " uy",
+			code:  1,
+			board: "#..\n.##\n.#.\n",
+			last:  "unsolved after 1 moves, 4 lights on",
+		},
+		{
+			name:  "undone then solved",
+			level: "starter/1",
+			keys:  " ujl\r",
+			board: "...\n...\n...\n",
+			last:  "solved in 1 moves",
+		},
+		{
+			name:  "no keys",
+			level: "starter/1",
+			code:  1,
+			board: ".#.\n###\n.#.\n",
+			last:  "unsolved after 0 moves, 5 lights on",
+		},
+		{
+			name:  "quit",
+			level: "starter/1",
+			keys:  "qjl\n",
+			code:  1,
+			board: ".#.\n###\n.#.\n",
+			last:  "unsolved after 0 moves, 5 lights on",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Level = test.level
+			s := &session{newline: "\n"}
+			first, err := setup(cfg, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if code := runHeadless(s, first, strings.NewReader(test.keys), &out); code != test.code {
+				t.Errorf("exit code %d, want %d", code, test.code)
+			}
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if board := strings.Join(lines[1:len(lines)-1], "\n") + "\n"; board != test.board {
+				t.Errorf("board\n%s\nwant\n%s", board, test.board)
+			}
+			if last := lines[len(lines)-1]; last != test.last {
+				t.Errorf("last line %q, want %q", last, test.last)
+			}
+		})
+	}
+}
+
+func TestHeadlessPuzzle(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Puzzle = "4x4-easy-7"
+	s := &session{newline: "\n"}
+	first, err := setup(cfg, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if code := runHeadless(s, first, strings.NewReader(""), &out); code != 1 {
+		t.Errorf("exit code %d, want 1", code)
+	}
+	if !strings.HasPrefix(out.String(), "4x4-easy-7\n") {
+		t.Errorf("output doesn't start with the puzzle code:\n%s", out.String())
+	}
+}
+
+// TestNoGL keeps the terminal front end buildable without cgo, GL or GLFW,
+// so that it runs over SSH and on CI machines.
+func TestNoGL(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	out, err := exec.Command(goTool, "list", "-deps", ".").Output()
+	if err != nil {
+		t.Fatalf("unable to list dependencies: %v", err)
+	}
+	for _, dep := range strings.Fields(string(out)) {
+		if strings.Contains(dep, "pixelgl") || strings.HasPrefix(dep, "github.com/go-gl/") {
+			t.Errorf("depends on %s", dep)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/explodes/gogames/colors"
+	"github.com/explodes/gogames/lightsout/board"
+	"github.com/faiface/pixel"
+)
+
+var (
+	squareColors = colors.Palette{
+		pixel.RGB(1, 0.1, 0.1),
+		pixel.RGB(0.3, 0.3, 1),
+		pixel.RGB(0.4, 0.5, 0.2),
+	}
+	offColor = pixel.RGB(0.15, 0.15, 0.15)
+)
+
+// renderer draws boards as text, each cell four columns wide: the cell's two
+// columns between brackets marking the cursor.
+type renderer struct {
+	// color draws lights as 24-bit background colors instead of # and .
+	color bool
+	// newline ends each line, \r\n while the terminal is in raw mode.
+	newline string
+
+	colors      []pixel.RGBA
+	stateColors colors.Palette
+}
+
+func newRenderer(b *board.Board, color bool, newline string) *renderer {
+	r := &renderer{
+		color:   color,
+		newline: newline,
+		colors:  squareColors.ColorGrid(b.Width(), b.Height()),
+	}
+	if states := b.Rules().States; states > 2 {
+		r.stateColors = colors.Hues(states-1, 0, 0.8, 1)
+	}
+	return r
+}
+
+// mark is a cell drawn with brackets around it.
+type mark struct {
+	x, y        int
+	shown       bool
+	left, right byte
+}
+
+// board draws b top row first, as on screen, with the marked cells bracketed.
+func (r *renderer) board(sb *strings.Builder, b *board.Board, marks ...mark) {
+	for y := b.Height() - 1; y >= 0; y-- {
+		if b.Rules().Hex && y%2 == 1 {
+			// odd rows are shifted right by half a cell
+			sb.WriteString("  ")
+		}
+		for x := 0; x < b.Width(); x++ {
+			left, right := byte(' '), byte(' ')
+			for _, m := range marks {
+				if m.shown && m.x == x && m.y == y {
+					left, right = m.left, m.right
+					break
+				}
+			}
+			sb.WriteByte(left)
+			r.cell(sb, b, x, y)
+			sb.WriteByte(right)
+		}
+		sb.WriteString(r.newline)
+	}
+}
+
+func (r *renderer) cell(sb *strings.Builder, b *board.Board, x, y int) {
+	state := b.State(x, y)
+	if !r.color {
+		switch {
+		case state == 0:
+			sb.WriteString(" .")
+		case b.Rules().States == 2:
+			sb.WriteString("##")
+		default:
+			fmt.Fprintf(sb, "%2d", state)
+		}
+		return
+	}
+	c := offColor
+	switch {
+	case state == 0:
+	case r.stateColors != nil:
+		c = r.stateColors.At(state - 1)
+	default:
+		c = r.colors[b.Index(x, y)]
+	}
+	fmt.Fprintf(sb, "\x1b[48;2;%d;%d;%dm  %s", channel(c.R), channel(c.G), channel(c.B), resetStyle)
+}
+
+// line writes a line of text.
+func (r *renderer) line(sb *strings.Builder, format string, args ...interface{}) {
+	fmt.Fprintf(sb, format, args...)
+	sb.WriteString(r.newline)
+}
+
+func channel(v float64) int {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 1:
+		return 255
+	}
+	return int(v*255 + 0.5)
+}
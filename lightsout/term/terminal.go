@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	resetStyle  = "\x1b[0m"
+)
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// rawMode puts the terminal on stdin into raw mode, so keys arrive as they are
+// pressed without being echoed, and returns a function restoring it.
+func rawMode() (func() error, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read terminal state: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("unable to enter raw mode: %v", err)
+	}
+	return func() error {
+		_, err := stty(strings.TrimSpace(saved))
+		return err
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
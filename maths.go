@@ -44,3 +44,70 @@ func SignedSqrt(x float64) float64 {
 	}
 	return math.Sqrt(x)
 }
+
+func DegToRad(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func RadToDeg(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns where value lies between a and b, 0 at a and 1 at b.
+func InverseLerp(a, b, value float64) float64 {
+	if a == b {
+		return 0
+	}
+	return (value - a) / (b - a)
+}
+
+func LerpVec(a, b pixel.Vec, t float64) pixel.Vec {
+	return pixel.V(Lerp(a.X, b.X, t), Lerp(a.Y, b.Y, t))
+}
+
+// Smoothstep returns 0 below edge0, 1 above edge1 and eases between them.
+func Smoothstep(edge0, edge1, x float64) float64 {
+	t := LimitWithinBounds(InverseLerp(edge0, edge1, x), 0, 1)
+	return t * t * (3 - 2*t)
+}
+
+// WrapAngle wraps an angle in radians into (-π, π].
+func WrapAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle <= -math.Pi {
+		angle += 2 * math.Pi
+	} else if angle > math.Pi {
+		angle -= 2 * math.Pi
+	}
+	return angle
+}
+
+// AngleDifference returns the shortest signed turn in radians from a to b.
+func AngleDifference(a, b float64) float64 {
+	return WrapAngle(b - a)
+}
+
+// LerpAngle interpolates between two angles in radians along the shortest
+// arc.
+func LerpAngle(a, b, t float64) float64 {
+	return WrapAngle(a + AngleDifference(a, b)*t)
+}
+
+// Project returns the projection of v onto onto.
+func Project(v, onto pixel.Vec) pixel.Vec {
+	lengthSq := onto.X*onto.X + onto.Y*onto.Y
+	if lengthSq == 0 {
+		return pixel.ZV
+	}
+	return onto.Scaled((v.X*onto.X + v.Y*onto.Y) / lengthSq)
+}
+
+// Reflect bounces v off a surface with the given normal.
+func Reflect(v, normal pixel.Vec) pixel.Vec {
+	normal = normal.Unit()
+	return v.Sub(normal.Scaled(2 * v.Dot(normal)))
+}
@@ -0,0 +1,104 @@
+package games
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWrapAngle(t *testing.T) {
+	tests := []struct {
+		angle, want float64
+	}{
+		{0, 0},
+		{math.Pi / 2, math.Pi / 2},
+		{math.Pi, math.Pi},
+		{-math.Pi, math.Pi},
+		{3 * math.Pi / 2, -math.Pi / 2},
+		{-3 * math.Pi / 2, math.Pi / 2},
+		{2 * math.Pi, 0},
+		{5 * math.Pi, math.Pi},
+		{-7 * math.Pi / 2, math.Pi / 2},
+	}
+	for _, test := range tests {
+		if got := WrapAngle(test.angle); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("WrapAngle(%v) = %v, want %v", test.angle, got, test.want)
+		}
+	}
+}
+
+func TestAngleDifference(t *testing.T) {
+	tests := []struct {
+		a, b, want float64
+	}{
+		{0, math.Pi / 2, math.Pi / 2},
+		{math.Pi / 2, 0, -math.Pi / 2},
+		// across ±π the short way is through π, not through 0
+		{3 * math.Pi / 4, -3 * math.Pi / 4, math.Pi / 2},
+		{-3 * math.Pi / 4, 3 * math.Pi / 4, -math.Pi / 2},
+		{0, 2 * math.Pi, 0},
+	}
+	for _, test := range tests {
+		if got := AngleDifference(test.a, test.b); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("AngleDifference(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestLerpAngle(t *testing.T) {
+	tests := []struct {
+		a, b, t, want float64
+	}{
+		{0, math.Pi / 2, 0.5, math.Pi / 4},
+		{0, math.Pi / 2, 0, 0},
+		{0, math.Pi / 2, 1, math.Pi / 2},
+		// halfway from 3π/4 to -3π/4 the short way is π
+		{3 * math.Pi / 4, -3 * math.Pi / 4, 0.5, math.Pi},
+		{-3 * math.Pi / 4, 3 * math.Pi / 4, 0.5, math.Pi},
+		{7 * math.Pi / 8, -7 * math.Pi / 8, 0.75, -15 * math.Pi / 16},
+	}
+	for _, test := range tests {
+		if got := LerpAngle(test.a, test.b, test.t); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("LerpAngle(%v, %v, %v) = %v, want %v", test.a, test.b, test.t, got, test.want)
+		}
+	}
+}
+
+func TestLerp(t *testing.T) {
+	tests := []struct {
+		a, b, t, want float64
+	}{
+		{0, 10, 0, 0},
+		{0, 10, 0.25, 2.5},
+		{0, 10, 1, 10},
+		{10, -10, 0.5, 0},
+		{0, 10, 2, 20},
+	}
+	for _, test := range tests {
+		if got := Lerp(test.a, test.b, test.t); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("Lerp(%v, %v, %v) = %v, want %v", test.a, test.b, test.t, got, test.want)
+		}
+		if test.a != test.b {
+			if got := InverseLerp(test.a, test.b, test.want); math.Abs(got-test.t) > 1e-9 {
+				t.Errorf("InverseLerp(%v, %v, %v) = %v, want %v", test.a, test.b, test.want, got, test.t)
+			}
+		}
+	}
+}
+
+func TestSmoothstep(t *testing.T) {
+	tests := []struct {
+		x, want float64
+	}{
+		{-1, 0},
+		{0, 0},
+		{0.5, 0.5},
+		{0.25, 0.15625},
+		{1, 1},
+		{2, 1},
+	}
+	for _, test := range tests {
+		if got := Smoothstep(0, 1, test.x); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("Smoothstep(0, 1, %v) = %v, want %v", test.x, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/explodes/gogames/rng"
+)
+
+// Link describes the network conditions a LossyConn simulates on outgoing
+// packets.
+type Link struct {
+	Latency time.Duration
+	// Jitter is the most a packet's latency varies either way, which also
+	// reorders packets.
+	Jitter time.Duration
+	// Loss is the chance in [0, 1] of dropping each packet.
+	Loss float64
+}
+
+// Perfect reports whether the link passes packets straight through.
+func (l Link) Perfect() bool {
+	return l.Latency <= 0 && l.Jitter <= 0 && l.Loss <= 0
+}
+
+// LossyConn wraps a packet connection, delaying and dropping the packets it
+// writes to simulate a real network over loopback.
+type LossyConn struct {
+	net.PacketConn
+	link Link
+
+	mu     sync.Mutex
+	rng    *rng.RNG
+	closed bool
+}
+
+func NewLossyConn(conn net.PacketConn, link Link, rng *rng.RNG) *LossyConn {
+	return &LossyConn{
+		PacketConn: conn,
+		link:       link,
+		rng:        rng,
+	}
+}
+
+// WriteTo queues the packet for delivery after the simulated latency. It
+// always reports success, as UDP does, even when the packet is dropped.
+func (c *LossyConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return 0, net.ErrClosed
+	}
+	drop := c.rng.Chance(c.link.Loss)
+	delay := c.link.Latency
+	if c.link.Jitter > 0 {
+		delay += time.Duration(c.rng.Range(-1, 1) * float64(c.link.Jitter))
+	}
+	c.mu.Unlock()
+
+	if drop {
+		return len(p), nil
+	}
+	if delay <= 0 {
+		return c.PacketConn.WriteTo(p, addr)
+	}
+	packet := append([]byte(nil), p...)
+	time.AfterFunc(delay, func() {
+		c.mu.Lock()
+		closed := c.closed
+		c.mu.Unlock()
+		if !closed {
+			c.PacketConn.WriteTo(packet, addr)
+		}
+	})
+	return len(p), nil
+}
+
+func (c *LossyConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.PacketConn.Close()
+}
+
+// ListenUDP opens a UDP socket, wrapped in a LossyConn unless the link is
+// perfect.
+func ListenUDP(address string, link Link, rng *rng.RNG) (net.PacketConn, error) {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	if link.Perfect() {
+		return conn, nil
+	}
+	return NewLossyConn(conn, link, rng), nil
+}
@@ -0,0 +1,79 @@
+package pathfinding
+
+import "math"
+
+// DistanceMap holds the cost from every cell to the nearest of a set of
+// goals, so any number of agents can walk downhill towards them without
+// searching. This is often called a Dijkstra map.
+type DistanceMap struct {
+	grid  Grid
+	conn  Connectivity
+	width int
+	costs []float64
+}
+
+// NewDistanceMap fills a distance map outwards from the goals.
+func NewDistanceMap(g Grid, goals []Point, conn Connectivity) *DistanceMap {
+	s := newSearch(g)
+	for _, goal := range goals {
+		if g.Passable(goal) {
+			s.relax(-1, goal, 0, 0)
+		}
+	}
+	for {
+		current, ok := s.pop()
+		if !ok {
+			break
+		}
+		Neighbours(g, s.point(current), conn, func(q Point, step float64) {
+			// walking from q to current costs entering current
+			s.relax(current, q, s.cost[current]+step*g.Cost(s.point(current)), 0)
+		})
+	}
+	return &DistanceMap{
+		grid:  g,
+		conn:  conn,
+		width: s.width,
+		costs: s.cost,
+	}
+}
+
+// Distance returns the cost from p to the nearest goal, or +Inf when none
+// can be reached.
+func (m *DistanceMap) Distance(p Point) float64 {
+	if !m.grid.Passable(p) {
+		return math.Inf(1)
+	}
+	return m.costs[p.X+p.Y*m.width]
+}
+
+// Next returns the neighbour of p that is closest to a goal. It returns false
+// at a goal or when no goal can be reached.
+func (m *DistanceMap) Next(p Point) (Point, bool) {
+	best, bestDistance := p, m.Distance(p)
+	if bestDistance == 0 || math.IsInf(bestDistance, 1) {
+		return p, false
+	}
+	Neighbours(m.grid, p, m.conn, func(q Point, step float64) {
+		if d := m.Distance(q); d < bestDistance {
+			best, bestDistance = q, d
+		}
+	})
+	return best, best != p
+}
+
+// Path walks downhill from p to the nearest goal.
+func (m *DistanceMap) Path(p Point) ([]Point, bool) {
+	if math.IsInf(m.Distance(p), 1) {
+		return nil, false
+	}
+	path := []Point{p}
+	for {
+		next, ok := m.Next(p)
+		if !ok {
+			return path, m.Distance(p) == 0
+		}
+		p = next
+		path = append(path, p)
+	}
+}
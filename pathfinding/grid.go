@@ -0,0 +1,152 @@
+package pathfinding
+
+import "math"
+
+// Point is a cell in a grid.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Grid is a map that paths are searched over.
+type Grid interface {
+	// Size returns the number of columns and rows.
+	Size() (width, height int)
+	// Passable reports whether a cell can be entered. Cells outside of the
+	// grid are never passable.
+	Passable(p Point) bool
+	// Cost is the cost of entering a cell, scaled by √2 for diagonal steps.
+	// A* finds the cheapest path when no cost is below 1.
+	Cost(p Point) float64
+}
+
+// Connectivity is which neighbours a cell can step to.
+type Connectivity int
+
+const (
+	// Four connects each cell to its orthogonal neighbours.
+	Four Connectivity = 4
+	// Eight also connects diagonal neighbours, but never cuts a corner past a
+	// blocked orthogonal neighbour.
+	Eight Connectivity = 8
+)
+
+var directions = []Point{
+	{1, 0}, {0, 1}, {-1, 0}, {0, -1},
+	{1, 1}, {-1, 1}, {-1, -1}, {1, -1},
+}
+
+// Neighbours calls fn with each cell that p can step to and the length of the
+// step.
+func Neighbours(g Grid, p Point, conn Connectivity, fn func(q Point, step float64)) {
+	for _, d := range directions[:4] {
+		if q := p.Add(d); g.Passable(q) {
+			fn(q, 1)
+		}
+	}
+	if conn != Eight {
+		return
+	}
+	for _, d := range directions[4:] {
+		if canStep(g, p, d) {
+			fn(p.Add(d), math.Sqrt2)
+		}
+	}
+}
+
+// canStep reports whether a step from p in direction d is allowed, which for
+// diagonals requires both orthogonal cells to be open.
+func canStep(g Grid, p Point, d Point) bool {
+	if !g.Passable(p.Add(d)) {
+		return false
+	}
+	if d.X != 0 && d.Y != 0 {
+		return g.Passable(Point{X: p.X + d.X, Y: p.Y}) && g.Passable(Point{X: p.X, Y: p.Y + d.Y})
+	}
+	return true
+}
+
+// Heuristic estimates the cost between two cells.
+func Heuristic(a, b Point, conn Connectivity) float64 {
+	dx, dy := math.Abs(float64(a.X-b.X)), math.Abs(float64(a.Y-b.Y))
+	if conn == Eight {
+		// octile distance
+		return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+	}
+	return dx + dy
+}
+
+// Blocked is the cost of a cell that cannot be entered.
+var Blocked = math.Inf(1)
+
+// Tiles is a Grid stored as a cost per cell.
+type Tiles struct {
+	width, height int
+	costs         []float64
+}
+
+var _ Grid = &Tiles{}
+
+// NewTiles creates a grid where every cell is open and costs 1.
+func NewTiles(width, height int) *Tiles {
+	t := &Tiles{
+		width:  width,
+		height: height,
+		costs:  make([]float64, width*height),
+	}
+	for i := range t.costs {
+		t.costs[i] = 1
+	}
+	return t
+}
+
+func (t *Tiles) Size() (int, int) {
+	return t.width, t.height
+}
+
+func (t *Tiles) Contains(p Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < t.width && p.Y < t.height
+}
+
+func (t *Tiles) Passable(p Point) bool {
+	return t.Contains(p) && !math.IsInf(t.costs[p.X+p.Y*t.width], 1)
+}
+
+func (t *Tiles) Cost(p Point) float64 {
+	if !t.Contains(p) {
+		return Blocked
+	}
+	return t.costs[p.X+p.Y*t.width]
+}
+
+// SetCost sets the cost of entering a cell. Blocked makes it impassable.
+func (t *Tiles) SetCost(p Point, cost float64) {
+	if t.Contains(p) {
+		t.costs[p.X+p.Y*t.width] = cost
+	}
+}
+
+// SetBlocked blocks or opens a cell, opening it with a cost of 1.
+func (t *Tiles) SetBlocked(p Point, blocked bool) {
+	if blocked {
+		t.SetCost(p, Blocked)
+	} else {
+		t.SetCost(p, 1)
+	}
+}
+
+// PathCost returns the total cost of walking a path.
+func PathCost(g Grid, path []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		step := 1.0
+		if path[i].X != path[i-1].X && path[i].Y != path[i-1].Y {
+			step = math.Sqrt2
+		}
+		total += step * g.Cost(path[i])
+	}
+	return total
+}
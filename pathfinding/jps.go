@@ -0,0 +1,177 @@
+package pathfinding
+
+import "math"
+
+// JPS finds a shortest path from start to goal with Jump Point Search, which
+// gives the same paths as A* with Eight connectivity on grids where every
+// open cell costs the same, while visiting far fewer cells. Cell costs are
+// ignored. The path includes every cell, not just the jump points.
+func JPS(g Grid, start, goal Point) ([]Point, bool) {
+	if !g.Passable(start) || !g.Passable(goal) {
+		return nil, false
+	}
+	s := newSearch(g)
+	s.relax(-1, start, 0, Heuristic(start, goal, Eight))
+	goalIndex := s.index(goal)
+	for {
+		current, ok := s.pop()
+		if !ok {
+			return nil, false
+		}
+		if current == goalIndex {
+			return expand(s.path(goalIndex)), true
+		}
+		p := s.point(current)
+		var parent *Point
+		if s.parent[current] >= 0 {
+			pp := s.point(s.parent[current])
+			parent = &pp
+		}
+		for _, n := range jpsNeighbours(g, p, parent) {
+			jumpPoint, ok := jump(g, n, p, goal)
+			if !ok {
+				continue
+			}
+			cost := s.cost[current] + Heuristic(p, jumpPoint, Eight)
+			s.relax(current, jumpPoint, cost, Heuristic(jumpPoint, goal, Eight))
+		}
+	}
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// jpsNeighbours prunes the neighbours of p to the ones worth jumping towards
+// given the direction it was reached from.
+func jpsNeighbours(g Grid, p Point, parent *Point) []Point {
+	open := func(dx, dy int) bool {
+		return g.Passable(Point{X: p.X + dx, Y: p.Y + dy})
+	}
+	var neighbours []Point
+	add := func(dx, dy int) {
+		neighbours = append(neighbours, Point{X: p.X + dx, Y: p.Y + dy})
+	}
+
+	if parent == nil {
+		Neighbours(g, p, Eight, func(q Point, step float64) {
+			neighbours = append(neighbours, q)
+		})
+		return neighbours
+	}
+
+	dx, dy := sign(p.X-parent.X), sign(p.Y-parent.Y)
+	switch {
+	case dx != 0 && dy != 0:
+		if open(0, dy) {
+			add(0, dy)
+		}
+		if open(dx, 0) {
+			add(dx, 0)
+		}
+		if open(0, dy) && open(dx, 0) && open(dx, dy) {
+			add(dx, dy)
+		}
+	case dx != 0:
+		next, up, down := open(dx, 0), open(0, 1), open(0, -1)
+		if next {
+			add(dx, 0)
+			if up && open(dx, 1) {
+				add(dx, 1)
+			}
+			if down && open(dx, -1) {
+				add(dx, -1)
+			}
+		}
+		if up {
+			add(0, 1)
+		}
+		if down {
+			add(0, -1)
+		}
+	default:
+		next, right, left := open(0, dy), open(1, 0), open(-1, 0)
+		if next {
+			add(0, dy)
+			if right && open(1, dy) {
+				add(1, dy)
+			}
+			if left && open(-1, dy) {
+				add(-1, dy)
+			}
+		}
+		if right {
+			add(1, 0)
+		}
+		if left {
+			add(-1, 0)
+		}
+	}
+	return neighbours
+}
+
+// jump moves from parent through p until it finds the goal, a cell with a
+// forced neighbour, or a wall.
+func jump(g Grid, p, parent, goal Point) (Point, bool) {
+	dx, dy := p.X-parent.X, p.Y-parent.Y
+	for {
+		if !g.Passable(p) {
+			return Point{}, false
+		}
+		if p == goal {
+			return p, true
+		}
+		open := func(x, y int) bool {
+			return g.Passable(Point{X: x, Y: y})
+		}
+		x, y := p.X, p.Y
+		switch {
+		case dx != 0 && dy != 0:
+			// a diagonal move stops where a straight jump finds something
+			if _, ok := jump(g, Point{X: x + dx, Y: y}, p, goal); ok {
+				return p, true
+			}
+			if _, ok := jump(g, Point{X: x, Y: y + dy}, p, goal); ok {
+				return p, true
+			}
+		case dx != 0:
+			if (open(x, y-1) && !open(x-dx, y-1)) || (open(x, y+1) && !open(x-dx, y+1)) {
+				return p, true
+			}
+		default:
+			if (open(x-1, y) && !open(x-1, y-dy)) || (open(x+1, y) && !open(x+1, y-dy)) {
+				return p, true
+			}
+		}
+		// diagonal steps may not cut corners
+		if !open(x+dx, y) || !open(x, y+dy) {
+			return Point{}, false
+		}
+		parent, p = p, Point{X: x + dx, Y: y + dy}
+	}
+}
+
+// expand fills in the cells between jump points, which always lie on a
+// straight or diagonal line.
+func expand(jumpPoints []Point) []Point {
+	if len(jumpPoints) == 0 {
+		return nil
+	}
+	path := []Point{jumpPoints[0]}
+	for _, next := range jumpPoints[1:] {
+		p := path[len(path)-1]
+		steps := int(math.Max(math.Abs(float64(next.X-p.X)), math.Abs(float64(next.Y-p.Y))))
+		d := Point{X: sign(next.X - p.X), Y: sign(next.Y - p.Y)}
+		for i := 0; i < steps; i++ {
+			p = p.Add(d)
+			path = append(path, p)
+		}
+	}
+	return path
+}
@@ -0,0 +1,103 @@
+package pathfinding
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/explodes/gogames/rng"
+)
+
+// The benchmarks cross a random grid the size of Life's.
+const (
+	benchSize    = 500
+	benchDensity = 0.2
+	benchSeed    = 1
+)
+
+var (
+	benchOnce sync.Once
+	benchGrid *Tiles
+	benchPath []Point
+)
+
+func randomGrid(size int, density float64, rng *rng.RNG) *Tiles {
+	grid := NewTiles(size, size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			grid.SetBlocked(Point{X: x, Y: y}, rng.Chance(density))
+		}
+	}
+	grid.SetBlocked(Point{}, false)
+	grid.SetBlocked(Point{X: size - 1, Y: size - 1}, false)
+	return grid
+}
+
+// crossing returns the benchmark grid and the corners to path between.
+func crossing(tb testing.TB) (grid *Tiles, start, goal Point) {
+	benchOnce.Do(func() {
+		benchGrid = randomGrid(benchSize, benchDensity, rng.New(benchSeed))
+		benchPath, _ = AStar(benchGrid, Point{}, Point{X: benchSize - 1, Y: benchSize - 1}, Eight)
+	})
+	if benchPath == nil {
+		tb.Fatal("no path across the benchmark grid")
+	}
+	return benchGrid, Point{}, Point{X: benchSize - 1, Y: benchSize - 1}
+}
+
+func TestAStarAndJPSAgree(t *testing.T) {
+	grid, start, goal := crossing(t)
+	jps, ok := JPS(grid, start, goal)
+	if !ok {
+		t.Fatal("JPS found no path where A* did")
+	}
+	astarCost, jpsCost := PathCost(grid, benchPath), PathCost(grid, jps)
+	if math.Abs(astarCost-jpsCost) > 1e-6 {
+		t.Errorf("A* path costs %.4f, JPS %.4f", astarCost, jpsCost)
+	}
+}
+
+func BenchmarkAStarFour500(b *testing.B) {
+	grid, start, goal := crossing(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AStar(grid, start, goal, Four)
+	}
+}
+
+func BenchmarkAStar500(b *testing.B) {
+	grid, start, goal := crossing(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AStar(grid, start, goal, Eight)
+	}
+}
+
+func BenchmarkJPS500(b *testing.B) {
+	grid, start, goal := crossing(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		JPS(grid, start, goal)
+	}
+}
+
+func BenchmarkDistanceMap500(b *testing.B) {
+	grid, _, goal := crossing(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewDistanceMap(grid, []Point{goal}, Eight)
+	}
+}
+
+func BenchmarkSmooth500(b *testing.B) {
+	grid, _, _ := crossing(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Smooth(grid, benchPath)
+	}
+}
@@ -0,0 +1,115 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"math"
+)
+
+type openNode struct {
+	index    int
+	priority float64
+}
+
+type openList []openNode
+
+func (l openList) Len() int            { return len(l) }
+func (l openList) Less(i, j int) bool  { return l[i].priority < l[j].priority }
+func (l openList) Swap(i, j int)       { l[i], l[j] = l[j], l[i] }
+func (l *openList) Push(x interface{}) { *l = append(*l, x.(openNode)) }
+func (l *openList) Pop() interface{} {
+	old := *l
+	n := old[len(old)-1]
+	*l = old[:len(old)-1]
+	return n
+}
+
+// search holds the per-cell bookkeeping shared by A*, JPS and Dijkstra.
+type search struct {
+	width  int
+	cost   []float64
+	parent []int
+	closed []bool
+	open   openList
+}
+
+func newSearch(g Grid) *search {
+	width, height := g.Size()
+	s := &search{
+		width:  width,
+		cost:   make([]float64, width*height),
+		parent: make([]int, width*height),
+		closed: make([]bool, width*height),
+	}
+	for i := range s.cost {
+		s.cost[i] = math.Inf(1)
+		s.parent[i] = -1
+	}
+	return s
+}
+
+func (s *search) index(p Point) int {
+	return p.X + p.Y*s.width
+}
+
+func (s *search) point(i int) Point {
+	return Point{X: i % s.width, Y: i / s.width}
+}
+
+// relax records a cheaper way to reach q and queues it.
+func (s *search) relax(from int, q Point, cost, estimate float64) {
+	i := s.index(q)
+	if s.closed[i] || cost >= s.cost[i] {
+		return
+	}
+	s.cost[i] = cost
+	s.parent[i] = from
+	heap.Push(&s.open, openNode{index: i, priority: cost + estimate})
+}
+
+// pop returns the cheapest open cell that hasn't been closed yet.
+func (s *search) pop() (int, bool) {
+	for s.open.Len() > 0 {
+		n := heap.Pop(&s.open).(openNode)
+		if !s.closed[n.index] {
+			s.closed[n.index] = true
+			return n.index, true
+		}
+	}
+	return -1, false
+}
+
+// path walks parents back from the goal.
+func (s *search) path(goal int) []Point {
+	var path []Point
+	for i := goal; i >= 0; i = s.parent[i] {
+		path = append(path, s.point(i))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// AStar finds the cheapest path from start to goal, including both. It
+// returns false when the goal can't be reached.
+func AStar(g Grid, start, goal Point, conn Connectivity) ([]Point, bool) {
+	if !g.Passable(start) || !g.Passable(goal) {
+		return nil, false
+	}
+	s := newSearch(g)
+	s.relax(-1, start, 0, Heuristic(start, goal, conn))
+	goalIndex := s.index(goal)
+	for {
+		current, ok := s.pop()
+		if !ok {
+			return nil, false
+		}
+		if current == goalIndex {
+			return s.path(goalIndex), true
+		}
+		p := s.point(current)
+		Neighbours(g, p, conn, func(q Point, step float64) {
+			s.relax(current, q, s.cost[current]+step*g.Cost(q), Heuristic(q, goal, conn))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package pathfinding
+
+// LineOfSight reports whether the straight line between the centers of a and
+// b only crosses passable cells. Where the line passes exactly through a
+// corner both cells beside it must be open, matching the no corner cutting
+// rule of Eight.
+func LineOfSight(g Grid, a, b Point) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	nx, ny := abs(dx), abs(dy)
+	sx, sy := sign(dx), sign(dy)
+	p := a
+	if !g.Passable(p) {
+		return false
+	}
+	// walk every cell the line touches, deciding between a horizontal and a
+	// vertical step by comparing (ix+0.5)/nx with (iy+0.5)/ny
+	for ix, iy := 0, 0; ix < nx || iy < ny; {
+		cmp := (1+2*ix)*ny - (1+2*iy)*nx
+		switch {
+		case cmp == 0:
+			if !g.Passable(Point{X: p.X + sx, Y: p.Y}) || !g.Passable(Point{X: p.X, Y: p.Y + sy}) {
+				return false
+			}
+			p.X += sx
+			p.Y += sy
+			ix++
+			iy++
+		case cmp < 0:
+			p.X += sx
+			ix++
+		default:
+			p.Y += sy
+			iy++
+		}
+		if !g.Passable(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Smooth removes the cells of a path that can be skipped by walking in a
+// straight line, leaving the corners. The result is meant for agents that
+// move freely between waypoints rather than cell by cell.
+func Smooth(g Grid, path []Point) []Point {
+	if len(path) < 3 {
+		return append([]Point(nil), path...)
+	}
+	smoothed := []Point{path[0]}
+	anchor := 0
+	for i := 2; i < len(path); i++ {
+		if !LineOfSight(g, path[anchor], path[i]) {
+			anchor = i - 1
+			smoothed = append(smoothed, path[anchor])
+		}
+	}
+	return append(smoothed, path[len(path)-1])
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,47 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Migration upgrades encoded state by one version. It receives the state as
+// written in the store's format and returns it re-encoded for the next version.
+type Migration func(state []byte) ([]byte, error)
+
+// Migrate registers the migration from version from to version from+1.
+func (s *Store) Migrate(from int, m Migration) {
+	s.migrations[from] = m
+}
+
+func (s *Store) migrate(version int, state []byte) ([]byte, error) {
+	if version > s.version {
+		return nil, fmt.Errorf("saved with version %d, newer than %d", version, s.version)
+	}
+	for ; version < s.version; version++ {
+		m, ok := s.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from version %d", version)
+		}
+		var err error
+		if state, err = m(state); err != nil {
+			return nil, fmt.Errorf("migration from version %d: %v", version, err)
+		}
+	}
+	return state, nil
+}
+
+// JSONMigration adapts a function that edits decoded JSON state in place into
+// a Migration for JSON stores.
+func JSONMigration(fn func(state map[string]interface{}) error) Migration {
+	return func(data []byte) ([]byte, error) {
+		state := make(map[string]interface{})
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, err
+		}
+		if err := fn(state); err != nil {
+			return nil, err
+		}
+		return json.Marshal(state)
+	}
+}
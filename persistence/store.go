@@ -0,0 +1,199 @@
+package persistence
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSave is returned by Load when a slot has never been saved.
+var ErrNoSave = errors.New("no saved game")
+
+// Format is the encoding used for save files.
+type Format int
+
+const (
+	JSON Format = iota
+	Gob
+)
+
+func (f Format) extension() string {
+	if f == Gob {
+		return ".gob"
+	}
+	return ".json"
+}
+
+func (f Format) marshal(v interface{}) ([]byte, error) {
+	if f == Gob {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
+func (f Format) unmarshal(data []byte, v interface{}) error {
+	if f == Gob {
+		return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// envelope wraps saved state with its version. State is encoded separately so
+// that it can be migrated before being decoded into the current type.
+type envelope struct {
+	Version int
+	Saved   time.Time
+	State   []byte
+}
+
+type jsonEnvelope struct {
+	Version int             `json:"version"`
+	Saved   time.Time       `json:"saved"`
+	State   json.RawMessage `json:"state"`
+}
+
+// Store saves and loads versioned game state in named slots.
+type Store struct {
+	dir        string
+	version    int
+	format     Format
+	migrations map[int]Migration
+}
+
+// Dir returns the save directory of a game under the user's config directory.
+func Dir(game string) (string, error) {
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config, "gogames", game), nil
+}
+
+// NewStore creates a store in the game's save directory. version is the
+// version of the state currently written by the game.
+func NewStore(game string, version int, format Format) (*Store, error) {
+	dir, err := Dir(game)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find save directory: %v", err)
+	}
+	return NewStoreInDir(dir, version, format), nil
+}
+
+// NewStoreInDir creates a store that keeps its files in dir.
+func NewStoreInDir(dir string, version int, format Format) *Store {
+	return &Store{
+		dir:        dir,
+		version:    version,
+		format:     format,
+		migrations: make(map[int]Migration),
+	}
+}
+
+// Dir returns the directory the store keeps its files in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) path(slot string) string {
+	return filepath.Join(s.dir, slot+s.format.extension())
+}
+
+// Save writes state to a slot, replacing any previous save atomically.
+func (s *Store) Save(slot string, state interface{}) error {
+	encoded, err := s.format.marshal(state)
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %v", slot, err)
+	}
+
+	var data []byte
+	now := time.Now()
+	if s.format == JSON {
+		data, err = json.MarshalIndent(jsonEnvelope{Version: s.version, Saved: now, State: encoded}, "", "  ")
+	} else {
+		data, err = s.format.marshal(envelope{Version: s.version, Saved: now, State: encoded})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to encode %s: %v", slot, err)
+	}
+
+	if err := WriteFileAtomic(s.path(slot), data, 0644); err != nil {
+		return fmt.Errorf("unable to write %s: %v", slot, err)
+	}
+	return nil
+}
+
+// Load reads a slot into state, running migrations if it was saved by an
+// older version. It returns ErrNoSave if the slot does not exist.
+func (s *Store) Load(slot string, state interface{}) error {
+	data, err := ioutil.ReadFile(s.path(slot))
+	if os.IsNotExist(err) {
+		return ErrNoSave
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %v", slot, err)
+	}
+
+	var env envelope
+	if s.format == JSON {
+		var je jsonEnvelope
+		err = json.Unmarshal(data, &je)
+		env = envelope{Version: je.Version, Saved: je.Saved, State: je.State}
+	} else {
+		err = s.format.unmarshal(data, &env)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to decode %s: %v", slot, err)
+	}
+
+	migrated, err := s.migrate(env.Version, env.State)
+	if err != nil {
+		return fmt.Errorf("unable to migrate %s: %v", slot, err)
+	}
+	if err := s.format.unmarshal(migrated, state); err != nil {
+		return fmt.Errorf("unable to decode %s: %v", slot, err)
+	}
+	return nil
+}
+
+// Delete removes a slot. Deleting a slot that does not exist is not an error.
+func (s *Store) Delete(slot string) error {
+	err := os.Remove(s.path(slot))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Slots lists the saved slots.
+func (s *Store) Slots() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var slots []string
+	ext := s.format.extension()
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		slots = append(slots, strings.TrimSuffix(name, ext))
+	}
+	sort.Strings(slots)
+	return slots, nil
+}
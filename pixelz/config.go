@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/profiler"
+)
+
+type Config struct {
+	Window       config.Window   `json:"window"`
+	SlowmoFactor float64         `json:"slowmo_factor" help:"how much holding SPACE slows down time" min:"1"`
+	Seed         int64           `json:"seed" help:"random seed, picked from the clock when 0"`
+	Profile      profiler.Config `json:"profile"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Window: config.Window{
+			Width:        1024,
+			Height:       768,
+			CanvasWidth:  1024 / 2,
+			CanvasHeight: 768 / 2,
+			MaxFps:       60,
+		},
+		SlowmoFactor: 10,
+		Profile: profiler.Config{
+			Frames: 120,
+			CPU:    true,
+			Heap:   true,
+		},
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/config"
+	"github.com/explodes/gogames/profiler"
+	"github.com/explodes/gogames/rng"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
@@ -10,20 +13,10 @@ import (
 	"golang.org/x/image/colornames"
 	"golang.org/x/image/font/basicfont"
 	"math"
-	"math/rand"
 	"os"
 	"time"
 )
 
-const (
-	width        = 1024
-	height       = 768
-	canvasWidth  = width / 2
-	canvasHeight = height / 2
-	maxFps       = 60
-	slowmoFactor = 10
-)
-
 type particle struct {
 	*games.Physics
 
@@ -35,6 +28,8 @@ type particle struct {
 	shouldTwinkle bool
 	twinkleLife   float64
 	twinkled      bool
+
+	rng *rng.RNG
 }
 
 var _ games.Updater = &particle{}
@@ -50,7 +45,7 @@ func (d *particle) Update(dt float64) {
 	if d.shouldTwinkle {
 		d.twinkleLife -= 12 * dt // 12 twinkles per second
 		if !d.twinkled && d.twinkleLife <= -12 {
-			d.twinkleLife = 20 + 15*rand.Float64()
+			d.twinkleLife = d.rng.Range(20, 35)
 			d.twinkled = true
 		}
 	}
@@ -80,23 +75,11 @@ func (d *particle) Draw(imd *imdraw.IMDraw) {
 	imd.Circle(size, 0)
 }
 
-func randomNiceColor() pixel.RGBA {
-again:
-	r := rand.Float64()
-	g := rand.Float64()
-	b := rand.Float64()
-	magnitude := math.Sqrt(r*r + g*g + b*b)
-	if magnitude == 0 {
-		goto again
-	}
-	return pixel.RGB(r/magnitude, g/magnitude, b/magnitude)
-}
-
-func randomFireColor() pixel.RGBA {
+func randomFireColor(rng *rng.RNG) pixel.RGBA {
 again:
-	r := 0.30 + 0.70*rand.Float64()
-	g := 0.10 + 0.35*rand.Float64()
-	b := 0.00 + 0.10*rand.Float64()
+	r := rng.Range(0.30, 1.00)
+	g := rng.Range(0.10, 0.45)
+	b := rng.Range(0.00, 0.10)
 	magnitude := math.Sqrt(r*r + g*g + b*b)
 	if magnitude == 0 {
 		goto again
@@ -104,58 +87,75 @@ again:
 	return pixel.RGB(r/magnitude, g/magnitude, b/magnitude)
 }
 
-func makeParticles() []*particle {
-	shouldTwinkle := rand.Intn(100) > 25 // ~25% chance of NOT twinkling
-	numParticles := 100 + rand.Intn(2000)
+func makeParticles(rng *rng.RNG) []*particle {
+	spawning := rng.Stream("particles")
+	colorRNG := rng.Stream("colors")
+	shouldTwinkle := spawning.Intn(100) > 25 // ~25% chance of NOT twinkling
+	numParticles := 100 + spawning.Intn(2000)
 	//numParticles := 1
 	particles := make([]*particle, 0, numParticles)
 	for i := 0; i < numParticles; i++ {
-		explosive := 600 + 200*rand.Float64()
-		force := -(explosive / 2) + explosive*rand.Float64()
-		angle := 2 * math.Pi * rand.Float64()
+		explosive := spawning.Range(600, 800)
+		force := spawning.Range(-explosive/2, explosive/2)
+		angle := spawning.Angle()
 		dir := pixel.V(math.Cos(angle)*force, math.Sin(angle)*force)
 
 		particle := &particle{
 			Physics:       games.NewPhysicsWithVelocity(0, 0, dir.X, dir.Y),
-			color:         randomFireColor(),
-			size:          2 + 5*rand.Float64(),
-			shrinkage:     0.7 + 0.1*rand.Float64(),
+			color:         randomFireColor(colorRNG),
+			size:          spawning.Range(2, 7),
+			shrinkage:     spawning.Range(0.7, 0.8),
 			shouldTwinkle: shouldTwinkle,
+			rng:           spawning,
 		}
 		particles = append(particles, particle)
 	}
 	return particles
 }
 
-func run() {
-	rand.Seed(time.Now().UnixNano())
+func run(cfg Config) {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rng.RandomSeed()
+	}
+	rng := rng.New(seed)
 
-	cfg := pixelgl.WindowConfig{
-		Title:     "Explosion",
-		Bounds:    pixel.R(0, 0, width, height),
+	winCfg := pixelgl.WindowConfig{
+		Title:     fmt.Sprintf("Explosion | seed %d", seed),
+		Bounds:    pixel.R(0, 0, float64(cfg.Window.Width), float64(cfg.Window.Height)),
 		VSync:     true,
 		Resizable: true,
 	}
-	win, err := pixelgl.NewWindow(cfg)
+	win, err := pixelgl.NewWindow(winCfg)
 	if err != nil {
 		exitWith(err, "unable to create window")
 	}
 
+	canvasWidth, canvasHeight := float64(cfg.Window.CanvasWidth), float64(cfg.Window.CanvasHeight)
 	canvas := pixelgl.NewCanvas(pixel.R(-canvasWidth/2, -canvasHeight/2, canvasWidth/2, canvasHeight/2))
 
 	basicAtlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
 
 	instructionsTxt := text.New(pixel.V(canvas.Bounds().Min.X+10, canvas.Bounds().Max.Y-basicAtlas.Ascent()-10), basicAtlas)
-	instructionsTxt.WriteString("Press ENTER to explode, hold SPACE to slow down time")
+	instructionsTxt.WriteString("Press ENTER to explode, hold SPACE to slow down time\nF3 toggles the profiler, F9 captures a profile")
 
-	basicTxt := text.New(pixel.V(canvas.Bounds().Min.X+10, canvas.Bounds().Max.Y-2*basicAtlas.Ascent()-10), basicAtlas)
+	basicTxt := text.New(pixel.V(canvas.Bounds().Min.X+10, canvas.Bounds().Max.Y-3*basicAtlas.LineHeight()-10), basicAtlas)
 
-	particles := makeParticles()
+	prof := profiler.New(profiler.DefaultHistory)
+	if err := prof.Capture(cfg.Profile); err != nil {
+		exitWith(err, "unable to start profiling")
+	}
+	graphBounds := pixel.R(canvas.Bounds().Min.X+10, canvas.Bounds().Min.Y+10, canvas.Bounds().Min.X+10+profiler.DefaultHistory, canvas.Bounds().Min.Y+70)
+	overlay := profiler.NewOverlay(prof, graphBounds, time.Second/time.Duration(cfg.Window.MaxFps))
+	overlayTxt := text.New(pixel.V(graphBounds.Min.X, graphBounds.Max.Y+5*basicAtlas.LineHeight()), basicAtlas)
+	showOverlay := false
+
+	particles := makeParticles(rng)
 
 	imd := imdraw.New(nil)
 	imd.Precision = 32
 
-	fpsLimit := games.NewFpsLimiter(maxFps)
+	fpsLimit := games.NewFpsLimiter(cfg.Window.MaxFps)
 
 	canvas.Clear(colornames.Black)
 
@@ -163,33 +163,67 @@ func run() {
 
 	for !win.Closed() {
 		fpsLimit.StartFrame()
+		if err := prof.StartFrame(); err != nil {
+			fmt.Printf("profiling stopped: %v\n", err)
+		}
 
 		dt := time.Since(last).Seconds()
 		last = time.Now()
 
 		if win.Pressed(pixelgl.KeySpace) {
-			dt /= slowmoFactor
+			dt /= cfg.SlowmoFactor
 		}
 
 		if win.JustPressed(pixelgl.KeyEnter) {
 			canvas.Clear(colornames.Black)
-			particles = makeParticles()
+			particles = makeParticles(rng)
 		}
 
+		if win.JustPressed(pixelgl.KeyF3) {
+			showOverlay = !showOverlay
+		}
+
+		if win.JustPressed(pixelgl.KeyF9) && !prof.Capturing() {
+			capture := cfg.Profile
+			if capture.Dir == "" {
+				capture.Dir = "profiles"
+			}
+			capture.Start = 0
+			if err := prof.Capture(capture); err != nil {
+				fmt.Printf("unable to start profiling: %v\n", err)
+			} else {
+				fmt.Printf("capturing %d frames into %s\n", capture.Frames, capture.Dir)
+			}
+		}
+
+		endUpdate := prof.Begin("update")
 		for _, d := range particles {
 			d.Update(dt)
 		}
+		endUpdate()
 
+		endDraw := prof.Begin("draw")
 		canvas.Clear(colornames.Black)
 		imd.Clear()
 		for _, d := range particles {
 			d.Draw(imd)
 		}
+		if showOverlay {
+			overlay.Draw(imd)
+		}
 		imd.Draw(canvas)
 
 		instructionsTxt.Draw(canvas, pixel.IM)
 		basicTxt.Draw(canvas, pixel.IM)
+		if showOverlay {
+			overlayTxt.Clear()
+			overlayTxt.Dot = overlayTxt.Orig
+			overlay.WriteLegend(overlayTxt)
+			overlayTxt.Draw(canvas, pixel.IM)
+		}
+		endDraw()
 
+		endPresent := prof.Begin("present")
 		// stretch the canvas to the window
 		win.Clear(colornames.White)
 		win.SetMatrix(pixel.IM.Scaled(pixel.ZV,
@@ -200,8 +234,12 @@ func run() {
 		).Moved(win.Bounds().Center()))
 		canvas.Draw(win, pixel.IM.Moved(canvas.Bounds().Center()))
 		win.Update()
+		endPresent()
 
 		fpsLimit.WaitForNextFrame()
+		if err := prof.EndFrame(); err != nil {
+			fmt.Printf("profiling stopped: %v\n", err)
+		}
 
 		basicTxt.Clear()
 		basicTxt.Dot = basicTxt.Orig
@@ -210,7 +248,13 @@ func run() {
 }
 
 func main() {
-	pixelgl.Run(run)
+	cfg := defaultConfig()
+	if err := config.Load("pixelz", &cfg, os.Args[1:]); err != nil {
+		exitWith(err, "invalid config")
+	}
+	pixelgl.Run(func() {
+		run(cfg)
+	})
 }
 
 func exitWith(err error, msg string, args ...interface{}) {
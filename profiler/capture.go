@@ -0,0 +1,143 @@
+package profiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+// Config selects a window of frames to capture runtime profiles for. It can
+// be embedded in a game's config.
+type Config struct {
+	Dir    string `json:"dir" help:"directory to write profiles to, profiling is off when empty"`
+	Start  int    `json:"start" help:"first frame to profile" min:"0"`
+	Frames int    `json:"frames" help:"number of frames to profile" min:"0"`
+	CPU    bool   `json:"cpu" help:"write a CPU profile"`
+	Heap   bool   `json:"heap" help:"write a heap profile at the end of the window"`
+	Trace  bool   `json:"trace" help:"write an execution trace"`
+}
+
+// Enabled reports whether the config asks for any profile.
+func (c Config) Enabled() bool {
+	return c.Dir != "" && c.Frames > 0 && (c.CPU || c.Heap || c.Trace)
+}
+
+type capture struct {
+	cfg       Config
+	stamp     string
+	cpuFile   *os.File
+	traceFile *os.File
+}
+
+// Capture profiles frames cfg.Start through cfg.Start+cfg.Frames-1, counted
+// from the profiler's first frame. A window that has already started is
+// moved to begin with the next frame.
+func (p *Profiler) Capture(cfg Config) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if p.capture != nil {
+		return fmt.Errorf("a capture is already running")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return err
+	}
+	next := p.frame
+	if p.inFrame {
+		next++
+	}
+	if cfg.Start < next {
+		cfg.Start = next
+	}
+	p.capture = &capture{
+		cfg:   cfg,
+		stamp: time.Now().Format("20060102-150405"),
+	}
+	return nil
+}
+
+// Capturing reports whether a capture is pending or running.
+func (p *Profiler) Capturing() bool {
+	return p.capture != nil
+}
+
+func (c *capture) path(kind, ext string) string {
+	return filepath.Join(c.cfg.Dir, fmt.Sprintf("%s-%s.%s", kind, c.stamp, ext))
+}
+
+func (c *capture) startFrame(frame int) error {
+	if frame != c.cfg.Start {
+		return nil
+	}
+	if c.cfg.CPU {
+		f, err := os.Create(c.path("cpu", "pprof"))
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		c.cpuFile = f
+	}
+	if c.cfg.Trace {
+		f, err := os.Create(c.path("trace", "out"))
+		if err != nil {
+			c.stop()
+			return err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			c.stop()
+			return err
+		}
+		c.traceFile = f
+	}
+	return nil
+}
+
+// endFrame finishes the capture after its last frame.
+func (c *capture) endFrame(frame int) (bool, error) {
+	if frame < c.cfg.Start+c.cfg.Frames-1 {
+		return false, nil
+	}
+	err := c.stop()
+	if c.cfg.Heap {
+		if heapErr := c.writeHeap(); err == nil {
+			err = heapErr
+		}
+	}
+	return true, err
+}
+
+func (c *capture) stop() error {
+	var err error
+	if c.cpuFile != nil {
+		pprof.StopCPUProfile()
+		err = c.cpuFile.Close()
+		c.cpuFile = nil
+	}
+	if c.traceFile != nil {
+		trace.Stop()
+		if closeErr := c.traceFile.Close(); err == nil {
+			err = closeErr
+		}
+		c.traceFile = nil
+	}
+	return err
+}
+
+func (c *capture) writeHeap() error {
+	f, err := os.Create(c.path("heap", "pprof"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// collect garbage so the profile shows live objects
+	runtime.GC()
+	return pprof.WriteHeapProfile(f)
+}
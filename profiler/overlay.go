@@ -0,0 +1,120 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+var sectionColors = []pixel.RGBA{
+	pixel.RGB(0.2, 0.8, 0.2),
+	pixel.RGB(0.2, 0.5, 1),
+	pixel.RGB(1, 0.6, 0.1),
+	pixel.RGB(0.9, 0.2, 0.9),
+	pixel.RGB(0.1, 0.9, 0.9),
+}
+
+var (
+	backgroundColor = pixel.RGBA{R: 0, G: 0, B: 0, A: 0.6}
+	idleColor       = pixel.RGBA{R: 0.3, G: 0.3, B: 0.3, A: 0.6}
+	budgetColor     = pixel.RGB(1, 0.1, 0.1)
+)
+
+var _ games.Drawer = &Overlay{}
+
+// Overlay draws a rolling graph of frame times, one stacked bar per frame
+// with a color per section. Time outside of any section, such as waiting for
+// the next frame, is drawn in gray on top.
+type Overlay struct {
+	Profiler *Profiler
+	Bounds   pixel.Rect
+	// Budget is drawn as a red line at half the height of the graph.
+	Budget time.Duration
+}
+
+// NewOverlay creates an overlay for p. budget is usually the frame time of the
+// target frame rate.
+func NewOverlay(p *Profiler, bounds pixel.Rect, budget time.Duration) *Overlay {
+	return &Overlay{
+		Profiler: p,
+		Bounds:   bounds,
+		Budget:   budget,
+	}
+}
+
+// SectionColor returns the color a section is drawn with.
+func (o *Overlay) SectionColor(section int) pixel.RGBA {
+	return sectionColors[section%len(sectionColors)]
+}
+
+func (o *Overlay) Draw(imd *imdraw.IMDraw) {
+	p := o.Profiler
+	b := o.Bounds
+
+	imd.Color = backgroundColor
+	imd.Push(b.Min, b.Max)
+	imd.Rectangle(0)
+
+	scale := b.H() / (2 * o.Budget.Seconds())
+	height := func(d time.Duration) float64 {
+		return math.Min(b.H(), d.Seconds()*scale)
+	}
+	barWidth := b.W() / float64(p.size)
+	sections := p.Sections()
+
+	for i := 0; i < p.Frames(); i++ {
+		x := b.Min.X + float64(i)*barWidth
+		y := b.Min.Y
+		for s, name := range sections {
+			h := height(p.SectionTime(name, i))
+			top := math.Min(b.Max.Y, y+h)
+			if top > y {
+				imd.Color = o.SectionColor(s)
+				imd.Push(pixel.V(x, y), pixel.V(x+barWidth, top))
+				imd.Rectangle(0)
+			}
+			y = top
+		}
+		if top := b.Min.Y + height(p.FrameTime(i)); top > y {
+			imd.Color = idleColor
+			imd.Push(pixel.V(x, y), pixel.V(x+barWidth, top))
+			imd.Rectangle(0)
+		}
+	}
+
+	budget := b.Min.Y + b.H()/2
+	imd.Color = budgetColor
+	imd.Push(pixel.V(b.Min.X, budget), pixel.V(b.Max.X, budget))
+	imd.Line(1)
+}
+
+// WriteLegend writes the average time of each section, and of whole frames.
+// When w is a *text.Text each section is written in its graph color.
+func (o *Overlay) WriteLegend(w io.Writer) {
+	p := o.Profiler
+	txt, colored := w.(*text.Text)
+	for s, name := range p.Sections() {
+		if colored {
+			txt.Color = o.SectionColor(s)
+		}
+		fmt.Fprintf(w, "%-8s %6.2fms\n", name, milliseconds(p.Average(name)))
+	}
+	if colored {
+		txt.Color = colornames.White
+	}
+	fmt.Fprintf(w, "%-8s %6.2fms (worst %.2fms)\n", "frame", milliseconds(p.Average("")), milliseconds(p.Worst()))
+	if p.Capturing() {
+		fmt.Fprintln(w, "capturing profile...")
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return d.Seconds() * 1000
+}
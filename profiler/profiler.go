@@ -0,0 +1,169 @@
+package profiler
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHistory is the number of frames kept for the rolling graph.
+const DefaultHistory = 240
+
+// Profiler times named sections of each frame, such as update, draw and
+// present, and keeps a rolling history of the results.
+type Profiler struct {
+	sections []string
+	history  map[string][]time.Duration
+	frames   []time.Duration
+	current  map[string]time.Duration
+	size     int
+	next     int
+	count    int
+
+	frame      int
+	frameStart time.Time
+	inFrame    bool
+
+	capture *capture
+}
+
+// New creates a profiler that remembers the last history frames.
+func New(history int) *Profiler {
+	if history <= 0 {
+		history = DefaultHistory
+	}
+	return &Profiler{
+		history: make(map[string][]time.Duration),
+		frames:  make([]time.Duration, history),
+		current: make(map[string]time.Duration),
+		size:    history,
+	}
+}
+
+// StartFrame marks the beginning of a frame. An error means a capture
+// failed to start and has been dropped.
+func (p *Profiler) StartFrame() error {
+	p.frameStart = time.Now()
+	p.inFrame = true
+	for name := range p.current {
+		p.current[name] = 0
+	}
+	if p.capture != nil {
+		if err := p.capture.startFrame(p.frame); err != nil {
+			p.capture = nil
+			return fmt.Errorf("unable to start capture: %v", err)
+		}
+	}
+	return nil
+}
+
+// Begin starts timing a section and returns the function that ends it, so a
+// section can be timed with defer p.Begin("update")(). Sections entered more
+// than once in a frame accumulate.
+func (p *Profiler) Begin(name string) func() {
+	if _, ok := p.history[name]; !ok {
+		p.sections = append(p.sections, name)
+		p.history[name] = make([]time.Duration, p.size)
+	}
+	start := time.Now()
+	return func() {
+		p.current[name] += time.Since(start)
+	}
+}
+
+// Time runs fn as a named section.
+func (p *Profiler) Time(name string, fn func()) {
+	end := p.Begin(name)
+	fn()
+	end()
+}
+
+// EndFrame records the frame's sections into the history. An error means a
+// capture failed to write its profiles.
+func (p *Profiler) EndFrame() error {
+	p.frames[p.next] = time.Since(p.frameStart)
+	for _, name := range p.sections {
+		p.history[name][p.next] = p.current[name]
+	}
+	p.next = (p.next + 1) % p.size
+	if p.count < p.size {
+		p.count++
+	}
+
+	var err error
+	if p.capture != nil {
+		var done bool
+		done, err = p.capture.endFrame(p.frame)
+		if done || err != nil {
+			p.capture = nil
+		}
+		if err != nil {
+			err = fmt.Errorf("unable to finish capture: %v", err)
+		}
+	}
+	p.frame++
+	p.inFrame = false
+	return err
+}
+
+// Frame returns the number of frames profiled so far.
+func (p *Profiler) Frame() int {
+	return p.frame
+}
+
+// Sections returns the section names in the order they were first seen.
+func (p *Profiler) Sections() []string {
+	return append([]string(nil), p.sections...)
+}
+
+// Frames returns the number of frames in the history.
+func (p *Profiler) Frames() int {
+	return p.count
+}
+
+// FrameTime returns the total time of a frame in the history, where 0 is the
+// oldest frame.
+func (p *Profiler) FrameTime(i int) time.Duration {
+	return p.frames[p.index(i)]
+}
+
+// SectionTime returns the time spent in a section during a frame in the
+// history, where 0 is the oldest frame.
+func (p *Profiler) SectionTime(name string, i int) time.Duration {
+	times, ok := p.history[name]
+	if !ok {
+		return 0
+	}
+	return times[p.index(i)]
+}
+
+func (p *Profiler) index(i int) int {
+	return (p.next - p.count + i + p.size) % p.size
+}
+
+// Average returns the mean time of a section over the history. An empty name
+// averages whole frames.
+func (p *Profiler) Average(name string) time.Duration {
+	if p.count == 0 {
+		return 0
+	}
+	var total time.Duration
+	for i := 0; i < p.count; i++ {
+		if name == "" {
+			total += p.FrameTime(i)
+		} else {
+			total += p.SectionTime(name, i)
+		}
+	}
+	return total / time.Duration(p.count)
+}
+
+// Worst returns the longest frame in the history.
+func (p *Profiler) Worst() time.Duration {
+	var worst time.Duration
+	for i := 0; i < p.count; i++ {
+		if t := p.FrameTime(i); t > worst {
+			worst = t
+		}
+	}
+	return worst
+}
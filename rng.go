@@ -0,0 +1,24 @@
+package games
+
+import (
+	"math"
+
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+// PointInRect returns a point uniformly distributed in rect.
+func PointInRect(r *rng.RNG, rect pixel.Rect) pixel.Vec {
+	return pixel.V(r.Range(rect.Min.X, rect.Max.X), r.Range(rect.Min.Y, rect.Max.Y))
+}
+
+// PointInCircle returns a point uniformly distributed in the circle.
+func PointInCircle(r *rng.RNG, center pixel.Vec, radius float64) pixel.Vec {
+	distance := radius * math.Sqrt(r.Float64())
+	return center.Add(pixel.V(distance, 0).Rotated(r.Angle()))
+}
+
+// PointOnCircle returns a point uniformly distributed on the circle's edge.
+func PointOnCircle(r *rng.RNG, center pixel.Vec, radius float64) pixel.Vec {
+	return center.Add(pixel.V(radius, 0).Rotated(r.Angle()))
+}
@@ -0,0 +1,213 @@
+package rng
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// splitmix is a rand.Source whose whole state is one uint64, so it can be
+// saved and restored.
+type splitmix struct {
+	state uint64
+}
+
+var _ rand.Source64 = &splitmix{}
+
+func (s *splitmix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitmix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *splitmix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// RandomSeed returns a seed taken from the clock.
+func RandomSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// RNG is a deterministic random number generator. Runs with the same seed
+// produce the same numbers, and named sub-streams let each system draw
+// numbers without disturbing the others.
+type RNG struct {
+	seed    int64
+	src     *splitmix
+	rand    *rand.Rand
+	streams map[string]*RNG
+}
+
+// State is a snapshot of an RNG and all of its sub-streams.
+type State struct {
+	Seed    int64            `json:"seed"`
+	State   uint64           `json:"state"`
+	Streams map[string]State `json:"streams,omitempty"`
+}
+
+// New creates an RNG whose numbers are determined entirely by seed. Streams
+// taken from it are seeded from seed and their names, not from the RNG's
+// current state, so drawing numbers never changes them.
+func New(seed int64) *RNG {
+	src := &splitmix{state: uint64(seed)}
+	return &RNG{
+		seed:    seed,
+		src:     src,
+		rand:    rand.New(src),
+		streams: make(map[string]*RNG),
+	}
+}
+
+// Seed returns the seed the RNG was created with.
+func (r *RNG) Seed() int64 {
+	return r.seed
+}
+
+// Stream returns the sub-stream with the given name, creating it on first
+// use. A stream's seed depends only on its parent's seed and its name, so it
+// produces the same numbers no matter how the parent is used.
+func (r *RNG) Stream(name string) *RNG {
+	if s, ok := r.streams[name]; ok {
+		return s
+	}
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	mix := &splitmix{state: uint64(r.seed) ^ h.Sum64()}
+	s := New(int64(mix.Uint64()))
+	r.streams[name] = s
+	return s
+}
+
+// Snapshot captures the state of the RNG and its sub-streams.
+func (r *RNG) Snapshot() State {
+	state := State{
+		Seed:  r.seed,
+		State: r.src.state,
+	}
+	if len(r.streams) > 0 {
+		state.Streams = make(map[string]State, len(r.streams))
+		for name, s := range r.streams {
+			state.Streams[name] = s.Snapshot()
+		}
+	}
+	return state
+}
+
+// Restore returns the RNG and its sub-streams to a snapshot. Streams created
+// after the snapshot are dropped.
+func (r *RNG) Restore(state State) {
+	r.seed = state.Seed
+	r.src.state = state.State
+	r.rand = rand.New(r.src)
+	streams := make(map[string]*RNG, len(state.Streams))
+	for name, s := range state.Streams {
+		stream, ok := r.streams[name]
+		if !ok {
+			stream = New(s.Seed)
+		}
+		stream.Restore(s)
+		streams[name] = stream
+	}
+	r.streams = streams
+}
+
+func (r *RNG) Int63() int64 {
+	return r.rand.Int63()
+}
+
+func (r *RNG) Uint64() uint64 {
+	return r.src.Uint64()
+}
+
+// Intn returns a number in [0, n).
+func (r *RNG) Intn(n int) int {
+	return r.rand.Intn(n)
+}
+
+// Float64 returns a number in [0, 1).
+func (r *RNG) Float64() float64 {
+	return r.rand.Float64()
+}
+
+// Chance returns true with probability p.
+func (r *RNG) Chance(p float64) bool {
+	return r.rand.Float64() < p
+}
+
+// Range returns a number in [min, max).
+func (r *RNG) Range(min, max float64) float64 {
+	return min + r.rand.Float64()*(max-min)
+}
+
+// IntRange returns a number in [min, max].
+func (r *RNG) IntRange(min, max int) int {
+	return min + r.rand.Intn(max-min+1)
+}
+
+// Normal returns a normally distributed number.
+func (r *RNG) Normal(mean, stddev float64) float64 {
+	return mean + r.rand.NormFloat64()*stddev
+}
+
+// Angle returns an angle in radians in [0, 2π).
+func (r *RNG) Angle() float64 {
+	return 2 * math.Pi * r.rand.Float64()
+}
+
+// WeightedChoice returns an index into weights chosen with probability
+// proportional to its weight, or -1 when no weight is positive. Negative
+// weights count as zero.
+func (r *RNG) WeightedChoice(weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	target := r.rand.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if target < w {
+			return i
+		}
+		target -= w
+		last = i
+	}
+	// rounding left target just past the last weight
+	return last
+}
+
+// Shuffle randomizes the order of n elements with swap.
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	r.rand.Shuffle(n, swap)
+}
+
+// Perm returns a random permutation of [0, n).
+func (r *RNG) Perm(n int) []int {
+	return r.rand.Perm(n)
+}
+
+// StreamNames returns the names of the sub-streams created so far.
+func (r *RNG) StreamNames() []string {
+	names := make([]string, 0, len(r.streams))
+	for name := range r.streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
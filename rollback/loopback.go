@@ -0,0 +1,144 @@
+package rollback
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames/rng"
+)
+
+// pipeEnd is one end of an in-memory link whose delay is counted in frames,
+// so loopback runs are repeatable.
+type pipeEnd struct {
+	link  *pipeLink
+	other *pipeEnd
+	queue []delayed
+}
+
+type delayed struct {
+	deliverAt int
+	packet    []byte
+}
+
+type pipeLink struct {
+	now   int
+	delay int
+	// jitter is the most a packet's delay varies, in frames.
+	jitter int
+	loss   float64
+	rng    *rng.RNG
+}
+
+var _ Transport = &pipeEnd{}
+
+func (e *pipeEnd) Send(packet []byte) error {
+	l := e.link
+	if l.rng.Chance(l.loss) {
+		return nil
+	}
+	delay := l.delay
+	if l.jitter > 0 {
+		delay += l.rng.IntRange(-l.jitter, l.jitter)
+	}
+	e.other.queue = append(e.other.queue, delayed{
+		deliverAt: l.now + delay,
+		packet:    append([]byte(nil), packet...),
+	})
+	return nil
+}
+
+func (e *pipeEnd) Receive() ([]byte, bool) {
+	for i, d := range e.queue {
+		if d.deliverAt <= e.link.now {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			return d.packet, true
+		}
+	}
+	return nil, false
+}
+
+// LoopbackConfig describes a simulated run between two peers in one process.
+type LoopbackConfig struct {
+	Session Config
+	Frames  int
+	// Delay is the one way latency in frames, varied by up to Jitter.
+	Delay  int
+	Jitter int
+	// Loss is the chance of dropping each packet.
+	Loss float64
+	Seed int64
+}
+
+// LoopbackResult reports how each peer coped.
+type LoopbackResult struct {
+	Stats [2]Stats
+	// Converged is true when both peers ended in the same state.
+	Converged bool
+}
+
+// RunLoopback plays two sessions of a game against each other over a
+// simulated link. newGame must build identical games, and input supplies
+// each player's input for each of their local frames. After Frames ticks the
+// link is made perfect and both peers run until they agree on every input.
+func RunLoopback(cfg LoopbackConfig, newGame func() Game, input func(player, frame int) Input) (LoopbackResult, error) {
+	result, _, err := runLoopback(cfg, newGame, input)
+	return result, err
+}
+
+// runLoopback is RunLoopback, also returning the sessions for inspection.
+func runLoopback(cfg LoopbackConfig, newGame func() Game, input func(player, frame int) Input) (LoopbackResult, [2]*Session, error) {
+	var result LoopbackResult
+	rng := rng.New(cfg.Seed)
+	link := &pipeLink{delay: cfg.Delay, jitter: cfg.Jitter, loss: cfg.Loss, rng: rng.Stream("link")}
+	ends := [2]*pipeEnd{{link: link}, {link: link}}
+	ends[0].other, ends[1].other = ends[1], ends[0]
+
+	var gamesByPeer [2]Game
+	var sessions [2]*Session
+	for peer := range sessions {
+		sessionCfg := cfg.Session
+		sessionCfg.Players = 2
+		sessionCfg.Local = peer
+		gamesByPeer[peer] = newGame()
+		sessions[peer] = NewSession(gamesByPeer[peer], sessionCfg)
+		if err := sessions[peer].AddPeer(1-peer, ends[peer]); err != nil {
+			return result, sessions, err
+		}
+	}
+
+	step := func() {
+		for peer, s := range sessions {
+			frame := s.Frame()
+			// peers stop taking input at the same frame so their inputs match
+			if frame < cfg.Frames {
+				s.Advance(input(peer, frame))
+			} else {
+				s.Poll()
+			}
+		}
+		link.now++
+	}
+
+	for link.now < cfg.Frames {
+		step()
+	}
+	link.loss, link.jitter = 0, 0
+	// let stalled peers catch up and every input and rollback settle; on a
+	// bad link a peer can spend much of the run stalled
+	settle := 2*cfg.Frames + 4*(cfg.Delay+cfg.Session.MaxPrediction+cfg.Session.InputDelay) + 100
+	for link.now < settle {
+		step()
+		if sessions[0].Frame() == cfg.Frames && sessions[1].Frame() == cfg.Frames &&
+			sessions[0].confirmedFrame() >= cfg.Frames-1 && sessions[1].confirmedFrame() >= cfg.Frames-1 {
+			break
+		}
+	}
+	for peer, s := range sessions {
+		if s.Frame() != cfg.Frames {
+			return result, sessions, fmt.Errorf("peer %d only reached frame %d of %d", peer, s.Frame(), cfg.Frames)
+		}
+		result.Stats[peer] = s.Stats()
+	}
+	a, b := gamesByPeer[0].Save(), gamesByPeer[1].Save()
+	result.Converged = string(a) == string(b)
+	return result, sessions, nil
+}
@@ -0,0 +1,173 @@
+package rollback
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+const (
+	buttonUp Input = 1 << iota
+	buttonDown
+	buttonLeft
+	buttonRight
+
+	fieldSize = 256
+)
+
+// dots is a game of two dots collecting a coin. Everything is integer maths
+// so that it is deterministic on any machine.
+type dots struct {
+	Players [2]struct{ X, Y, Score int32 }
+	Coin    struct{ X, Y int32 }
+	Random  uint32
+}
+
+func newDots() Game {
+	d := &dots{Random: 12345}
+	d.Players[1].X, d.Players[1].Y = fieldSize/2, fieldSize/2
+	d.placeCoin()
+	return d
+}
+
+func (d *dots) next() int32 {
+	d.Random = d.Random*1664525 + 1013904223
+	return int32(d.Random>>8) % fieldSize
+}
+
+func (d *dots) placeCoin() {
+	d.Coin.X, d.Coin.Y = d.next(), d.next()
+}
+
+func wrap(v int32) int32 {
+	return (v%fieldSize + fieldSize) % fieldSize
+}
+
+func (d *dots) Step(inputs []Input) {
+	for i, in := range inputs {
+		p := &d.Players[i]
+		if in&buttonUp != 0 {
+			p.Y = wrap(p.Y + 2)
+		}
+		if in&buttonDown != 0 {
+			p.Y = wrap(p.Y - 2)
+		}
+		if in&buttonLeft != 0 {
+			p.X = wrap(p.X - 2)
+		}
+		if in&buttonRight != 0 {
+			p.X = wrap(p.X + 2)
+		}
+		dx, dy := p.X-d.Coin.X, p.Y-d.Coin.Y
+		if dx*dx+dy*dy <= 64 {
+			p.Score++
+			d.placeCoin()
+		}
+	}
+}
+
+func (d *dots) Save() []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, d)
+	return buf.Bytes()
+}
+
+func (d *dots) Load(state []byte) {
+	binary.Read(bytes.NewReader(state), binary.LittleEndian, d)
+}
+
+// input holds each button for a while, like a person would.
+func input(player, frame int) Input {
+	h := uint32(player+1)*2654435761 ^ uint32(frame/12)*40503
+	h ^= h >> 13
+	h *= 0x5bd1e995
+	return Input(h>>7) & (buttonUp | buttonDown | buttonLeft | buttonRight)
+}
+
+// drifting is dots that each peer plays slightly differently, to check that
+// desyncs are caught. Its peer isn't part of the saved state.
+type drifting struct {
+	dots
+	peer int32
+}
+
+func (d *drifting) Step(inputs []Input) {
+	d.dots.Step(inputs)
+	d.Players[0].X = wrap(d.Players[0].X + d.peer)
+}
+
+func (d *drifting) Save() []byte {
+	return d.dots.Save()
+}
+
+func (d *drifting) Load(state []byte) {
+	d.dots.Load(state)
+}
+
+var links = []struct {
+	name          string
+	delay, jitter int
+	loss          float64
+}{
+	{"perfect", 0, 0, 0},
+	{"lan", 1, 0, 0},
+	{"broadband", 4, 1, 0.01},
+	{"wifi", 6, 3, 0.05},
+	{"bad", 10, 4, 0.15},
+}
+
+func loopbackConfig(delay, jitter int, loss float64) LoopbackConfig {
+	return LoopbackConfig{
+		Session: DefaultConfig(0),
+		Frames:  3600,
+		Delay:   delay,
+		Jitter:  jitter,
+		Loss:    loss,
+		Seed:    1,
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	for _, link := range links {
+		t.Run(link.name, func(t *testing.T) {
+			result, sessions, err := runLoopback(loopbackConfig(link.delay, link.jitter, link.loss), newDots, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Converged {
+				t.Error("peers ended in different states")
+			}
+			for peer, stats := range result.Stats {
+				if stats.Desyncs > 0 {
+					t.Errorf("peer %d saw %d desyncs", peer, stats.Desyncs)
+				}
+			}
+			for peer, s := range sessions {
+				// only checksums for frames not yet checksummed locally are
+				// kept, at most a round trip's worth
+				if n := len(s.remoteChecksums); n > 2*(link.delay+link.jitter)+1 {
+					t.Errorf("peer %d still holds %d remote checksums", peer, n)
+				}
+			}
+		})
+	}
+}
+
+func TestLoopbackDesync(t *testing.T) {
+	peers := int32(0)
+	newDrifting := func() Game {
+		d := &drifting{dots: *newDots().(*dots), peer: peers}
+		peers++
+		return d
+	}
+	result, err := RunLoopback(loopbackConfig(4, 1, 0.01), newDrifting, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Converged {
+		t.Error("peers playing differently ended in the same state")
+	}
+	if result.Stats[0].Desyncs == 0 && result.Stats[1].Desyncs == 0 {
+		t.Error("no desyncs detected")
+	}
+}
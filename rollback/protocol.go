@@ -0,0 +1,74 @@
+package rollback
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	maxPacket = 1024
+	// maxInputsPerPacket bounds how many unacknowledged inputs are resent.
+	maxInputsPerPacket = 64
+)
+
+const (
+	msgInputs byte = iota + 1
+	msgChecksum
+)
+
+var errBadPacket = errors.New("malformed packet")
+
+// inputsPacket carries a player's inputs for consecutive frames starting at
+// start, and the newest frame the sender has contiguously received from the
+// recipient.
+type inputsPacket struct {
+	player int
+	ack    int
+	start  int
+	inputs []Input
+}
+
+func encodeInputs(p inputsPacket) []byte {
+	b := make([]byte, 0, 11+4*len(p.inputs))
+	b = append(b, msgInputs, byte(p.player))
+	b = binary.LittleEndian.AppendUint32(b, uint32(int32(p.ack)))
+	b = binary.LittleEndian.AppendUint32(b, uint32(p.start))
+	b = append(b, byte(len(p.inputs)))
+	for _, in := range p.inputs {
+		b = binary.LittleEndian.AppendUint32(b, uint32(in))
+	}
+	return b
+}
+
+func decodeInputs(b []byte) (inputsPacket, error) {
+	if len(b) < 11 {
+		return inputsPacket{}, errBadPacket
+	}
+	p := inputsPacket{
+		player: int(b[1]),
+		ack:    int(int32(binary.LittleEndian.Uint32(b[2:]))),
+		start:  int(binary.LittleEndian.Uint32(b[6:])),
+	}
+	count := int(b[10])
+	b = b[11:]
+	if len(b) < 4*count {
+		return inputsPacket{}, errBadPacket
+	}
+	for i := 0; i < count; i++ {
+		p.inputs = append(p.inputs, Input(binary.LittleEndian.Uint32(b[4*i:])))
+	}
+	return p, nil
+}
+
+func encodeChecksum(frame int, checksum uint32) []byte {
+	b := []byte{msgChecksum}
+	b = binary.LittleEndian.AppendUint32(b, uint32(frame))
+	return binary.LittleEndian.AppendUint32(b, checksum)
+}
+
+func decodeChecksum(b []byte) (int, uint32, error) {
+	if len(b) < 9 {
+		return 0, 0, errBadPacket
+	}
+	return int(binary.LittleEndian.Uint32(b[1:])), binary.LittleEndian.Uint32(b[5:]), nil
+}
@@ -0,0 +1,376 @@
+package rollback
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+// Input is one player's controls for one frame, usually a bit per button.
+type Input uint32
+
+// Game is a simulation that gives identical results on every peer for the
+// same inputs: no wall clock, no map iteration order, no unseeded randomness.
+type Game interface {
+	// Step advances one frame. inputs holds one input per player.
+	Step(inputs []Input)
+	// Save returns a copy of the entire simulation state.
+	Save() []byte
+	// Load restores a state returned by Save.
+	Load(state []byte)
+}
+
+// Config tunes a session.
+type Config struct {
+	Players int
+	// Local is the index of the player controlled on this peer.
+	Local int
+	// InputDelay is how many frames after being read local input takes
+	// effect. A little delay means fewer, shorter rollbacks.
+	InputDelay int
+	// MaxPrediction is how many frames the session runs ahead of the last
+	// confirmed remote input before it stalls to wait.
+	MaxPrediction int
+	// FrameRate is the fixed number of simulation frames per second used by
+	// Update.
+	FrameRate int
+}
+
+// DefaultConfig is for two players at 60 frames per second.
+func DefaultConfig(local int) Config {
+	return Config{
+		Players:       2,
+		Local:         local,
+		InputDelay:    2,
+		MaxPrediction: 8,
+		FrameRate:     60,
+	}
+}
+
+// Stats counts what the session had to do to keep up.
+type Stats struct {
+	Frames      int
+	Rollbacks   int
+	Resimulated int
+	Stalls      int
+	Desyncs     int
+}
+
+// Session runs a Game with rollback netcode: it simulates ahead using
+// predicted remote inputs, and when the real inputs arrive and differ it
+// rewinds to the first wrong frame and simulates forward again. It is a
+// games.Updater, though it doesn't import games so that servers and tests
+// build without GL.
+type Session struct {
+	cfg   Config
+	game  Game
+	peers map[int]Transport
+
+	frame int
+	// inputs[player][frame] holds confirmed inputs.
+	inputs []map[int]Input
+	// received[player] is the newest frame with every earlier input known.
+	received []int
+	// used[frame] is what each frame was simulated with.
+	used map[int][]Input
+	// acked[player] is the newest local frame that peer has confirmed.
+	acked    map[int]int
+	states   [][]byte
+	rollback int
+
+	checksums       map[int]uint32
+	remoteChecksums map[int]uint32
+	lastChecksum    int
+
+	accumulator float64
+	stats       Stats
+
+	// ReadInput supplies local input to Update.
+	ReadInput func() Input
+	// OnDesync is called when a peer's checksum for a confirmed frame
+	// differs from ours.
+	OnDesync func(frame int, local, remote uint32)
+}
+
+func NewSession(game Game, cfg Config) *Session {
+	s := &Session{
+		cfg:             cfg,
+		game:            game,
+		peers:           make(map[int]Transport),
+		inputs:          make([]map[int]Input, cfg.Players),
+		received:        make([]int, cfg.Players),
+		used:            make(map[int][]Input),
+		acked:           make(map[int]int),
+		states:          make([][]byte, cfg.MaxPrediction+cfg.InputDelay+2),
+		rollback:        -1,
+		checksums:       make(map[int]uint32),
+		remoteChecksums: make(map[int]uint32),
+	}
+	for p := range s.inputs {
+		s.inputs[p] = make(map[int]Input)
+		s.received[p] = -1
+	}
+	// nothing is pressed during the frames before local input takes effect
+	for f := 0; f < cfg.InputDelay; f++ {
+		s.confirm(cfg.Local, f, 0)
+	}
+	return s
+}
+
+// AddPeer connects the remote player through t.
+func (s *Session) AddPeer(player int, t Transport) error {
+	if player < 0 || player >= s.cfg.Players || player == s.cfg.Local {
+		return fmt.Errorf("invalid remote player %d", player)
+	}
+	s.peers[player] = t
+	s.acked[player] = -1
+	return nil
+}
+
+// Frame returns the number of frames simulated.
+func (s *Session) Frame() int {
+	return s.frame
+}
+
+func (s *Session) Stats() Stats {
+	return s.stats
+}
+
+// Update advances as many fixed frames as dt covers, reading local input
+// from ReadInput.
+func (s *Session) Update(dt float64) {
+	step := 1 / float64(s.cfg.FrameRate)
+	s.accumulator += dt
+	for s.accumulator >= step {
+		s.accumulator -= step
+		var in Input
+		if s.ReadInput != nil {
+			in = s.ReadInput()
+		}
+		if !s.Advance(in) {
+			// stalled waiting for a peer, don't try to catch up in a burst
+			s.accumulator = 0
+		}
+	}
+}
+
+// Advance handles incoming packets, rolls back if a prediction was wrong and
+// simulates one frame with local input. It returns false, without using
+// local, when too far ahead of a peer.
+func (s *Session) Advance(local Input) bool {
+	s.poll()
+	s.resimulate()
+
+	if s.frame-s.confirmedFrame() > s.cfg.MaxPrediction {
+		s.stats.Stalls++
+		s.sendInputs()
+		return false
+	}
+
+	s.confirm(s.cfg.Local, s.frame+s.cfg.InputDelay, local)
+	s.sendInputs()
+
+	s.simulate(s.frame)
+	s.frame++
+	s.stats.Frames++
+
+	s.sendChecksum()
+	s.prune()
+	return true
+}
+
+// Poll handles incoming packets without advancing, for use while paused.
+func (s *Session) Poll() {
+	s.poll()
+	s.resimulate()
+	s.sendInputs()
+}
+
+// confirmedFrame is the newest frame for which every input is known.
+func (s *Session) confirmedFrame() int {
+	confirmed := s.received[s.cfg.Local]
+	for p := range s.peers {
+		if s.received[p] < confirmed {
+			confirmed = s.received[p]
+		}
+	}
+	return confirmed
+}
+
+// confirm records a real input, scheduling a rollback if the frame was
+// already simulated with a different prediction.
+func (s *Session) confirm(player, frame int, in Input) {
+	if _, ok := s.inputs[player][frame]; ok {
+		return
+	}
+	s.inputs[player][frame] = in
+	for {
+		if _, ok := s.inputs[player][s.received[player]+1]; !ok {
+			break
+		}
+		s.received[player]++
+	}
+	if used, ok := s.used[frame]; ok && used[player] != in {
+		if s.rollback < 0 || frame < s.rollback {
+			s.rollback = frame
+		}
+	}
+}
+
+// input returns the confirmed input, or predicts that the player is still
+// holding whatever they held last.
+func (s *Session) input(player, frame int) Input {
+	if in, ok := s.inputs[player][frame]; ok {
+		return in
+	}
+	return s.inputs[player][s.received[player]]
+}
+
+func (s *Session) simulate(frame int) {
+	s.states[frame%len(s.states)] = s.game.Save()
+	inputs := make([]Input, s.cfg.Players)
+	for p := range inputs {
+		inputs[p] = s.input(p, frame)
+	}
+	s.used[frame] = inputs
+	s.game.Step(inputs)
+}
+
+func (s *Session) resimulate() {
+	if s.rollback < 0 {
+		return
+	}
+	from := s.rollback
+	s.rollback = -1
+	if from >= s.frame {
+		return
+	}
+	s.stats.Rollbacks++
+	s.game.Load(s.states[from%len(s.states)])
+	for f := from; f < s.frame; f++ {
+		s.simulate(f)
+		s.stats.Resimulated++
+	}
+}
+
+func (s *Session) poll() {
+	for player, t := range s.peers {
+		for {
+			packet, ok := t.Receive()
+			if !ok {
+				break
+			}
+			if len(packet) == 0 {
+				continue
+			}
+			switch packet[0] {
+			case msgInputs:
+				p, err := decodeInputs(packet)
+				if err != nil || p.player != player {
+					continue
+				}
+				if p.ack > s.acked[player] {
+					s.acked[player] = p.ack
+				}
+				for i, in := range p.inputs {
+					s.confirm(player, p.start+i, in)
+				}
+			case msgChecksum:
+				frame, checksum, err := decodeChecksum(packet)
+				if err != nil {
+					continue
+				}
+				s.remoteChecksums[frame] = checksum
+				s.compareChecksum(frame)
+			}
+		}
+	}
+}
+
+// sendInputs resends every local input each peer hasn't acknowledged.
+func (s *Session) sendInputs() {
+	local := s.cfg.Local
+	for player, t := range s.peers {
+		start := s.acked[player] + 1
+		end := s.received[local]
+		if end-start+1 > maxInputsPerPacket {
+			end = start + maxInputsPerPacket - 1
+		}
+		p := inputsPacket{player: local, ack: s.received[player], start: start}
+		for f := start; f <= end; f++ {
+			p.inputs = append(p.inputs, s.inputs[local][f])
+		}
+		t.Send(encodeInputs(p))
+	}
+}
+
+// sendChecksum shares the checksum of the newest state that no future
+// rollback can change: the start of the frame after the last confirmed one.
+func (s *Session) sendChecksum() {
+	frame := s.confirmedFrame() + 1
+	if frame >= s.frame {
+		frame = s.frame - 1
+	}
+	if frame <= s.lastChecksum {
+		return
+	}
+	s.lastChecksum = frame
+	s.checksums[frame] = crc32.ChecksumIEEE(s.states[frame%len(s.states)])
+	packet := encodeChecksum(frame, s.checksums[frame])
+	for _, t := range s.peers {
+		t.Send(packet)
+	}
+	s.compareChecksum(frame)
+}
+
+func (s *Session) compareChecksum(frame int) {
+	local, ok := s.checksums[frame]
+	if !ok {
+		return
+	}
+	remote, ok := s.remoteChecksums[frame]
+	if !ok {
+		return
+	}
+	delete(s.remoteChecksums, frame)
+	if local != remote {
+		s.stats.Desyncs++
+		if s.OnDesync != nil {
+			s.OnDesync(frame, local, remote)
+		}
+	}
+}
+
+// prune forgets frames that can no longer be rolled back to or resent.
+func (s *Session) prune() {
+	keep := s.frame - len(s.states)
+	for player := range s.peers {
+		if s.acked[player] < keep {
+			keep = s.acked[player]
+		}
+	}
+	for f := range s.used {
+		if f < keep {
+			delete(s.used, f)
+		}
+	}
+	for p, inputs := range s.inputs {
+		for f := range inputs {
+			// the newest received input is kept for predictions
+			if f < keep && f < s.received[p] {
+				delete(inputs, f)
+			}
+		}
+	}
+	for f := range s.checksums {
+		if f < keep-len(s.states) {
+			delete(s.checksums, f)
+		}
+	}
+	// remote checksums are deleted once compared, so those left at or before
+	// our last checksum are for frames we skipped and will never compare
+	for f := range s.remoteChecksums {
+		if f <= s.lastChecksum {
+			delete(s.remoteChecksums, f)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rollback
+
+import (
+	"net"
+	"sync"
+)
+
+// Transport carries packets to and from one peer. Delivery may be
+// unreliable and unordered; the session resends what matters.
+type Transport interface {
+	Send(packet []byte) error
+	// Receive returns the next packet without blocking.
+	Receive() ([]byte, bool)
+}
+
+// PacketTransport is a Transport over a packet connection such as UDP.
+type PacketTransport struct {
+	conn net.PacketConn
+	peer net.Addr
+
+	mu      sync.Mutex
+	packets [][]byte
+	err     error
+}
+
+var _ Transport = &PacketTransport{}
+
+// NewPacketTransport talks to peer over conn, ignoring packets from anyone
+// else.
+func NewPacketTransport(conn net.PacketConn, peer net.Addr) *PacketTransport {
+	t := &PacketTransport{conn: conn, peer: peer}
+	go t.read()
+	return t
+}
+
+func (t *PacketTransport) read() {
+	buf := make([]byte, maxPacket)
+	for {
+		n, addr, err := t.conn.ReadFrom(buf)
+		t.mu.Lock()
+		if err != nil {
+			t.err = err
+			t.mu.Unlock()
+			return
+		}
+		if addr.String() == t.peer.String() {
+			t.packets = append(t.packets, append([]byte(nil), buf[:n]...))
+		}
+		t.mu.Unlock()
+	}
+}
+
+func (t *PacketTransport) Send(packet []byte) error {
+	_, err := t.conn.WriteTo(packet, t.peer)
+	return err
+}
+
+func (t *PacketTransport) Receive() ([]byte, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.packets) == 0 {
+		return nil, false
+	}
+	p := t.packets[0]
+	t.packets = t.packets[1:]
+	return p, true
+}
+
+// Err returns the error that stopped reading, if any.
+func (t *PacketTransport) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
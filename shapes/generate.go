@@ -0,0 +1,145 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Star creates a star with the given number of points. innerRatio is the
+// radius of the notches between points as a fraction of radius, and rotation
+// is the angle in radians of the first point.
+func Star(center pixel.Vec, points int, radius, innerRatio, rotation float64) Shape {
+	s := make(Shape, 0, 2*points)
+	step := math.Pi / float64(points)
+	for i := 0; i < 2*points; i++ {
+		r := radius
+		if i%2 == 1 {
+			r *= innerRatio
+		}
+		s = append(s, center.Add(pixel.Unit(rotation+float64(i)*step).Scaled(r)))
+	}
+	return s
+}
+
+// RegularPolygon creates a polygon with equal sides whose first corner is at
+// angle rotation in radians.
+func RegularPolygon(center pixel.Vec, sides int, radius, rotation float64) Shape {
+	s := make(Shape, sides)
+	for i := range s {
+		s[i] = center.Add(pixel.Unit(rotation + 2*math.Pi*float64(i)/float64(sides)).Scaled(radius))
+	}
+	return s
+}
+
+// Circle approximates a circle with a regular polygon.
+func Circle(center pixel.Vec, radius float64, segments int) Shape {
+	return RegularPolygon(center, segments, radius, 0)
+}
+
+// Arc creates an open polyline along a circle from angle start to end in
+// radians, counter-clockwise when end > start.
+func Arc(center pixel.Vec, radius, start, end float64, segments int) Shape {
+	s := make(Shape, segments+1)
+	for i := range s {
+		angle := start + (end-start)*float64(i)/float64(segments)
+		s[i] = center.Add(pixel.Unit(angle).Scaled(radius))
+	}
+	return s
+}
+
+// Sector creates a closed pie slice from start to end in radians.
+func Sector(center pixel.Vec, radius, start, end float64, segments int) Shape {
+	return append(Shape{center}, Arc(center, radius, start, end, segments)...)
+}
+
+// ArcBand creates the band between two radii from start to end in radians,
+// split into one convex quad per segment so each can be filled or used for
+// collision on its own.
+func ArcBand(center pixel.Vec, inner, outer, start, end float64, segments int) []Shape {
+	innerArc := Arc(center, inner, start, end, segments)
+	outerArc := Arc(center, outer, start, end, segments)
+	band := make([]Shape, segments)
+	for i := range band {
+		band[i] = Shape{innerArc[i], outerArc[i], outerArc[i+1], innerArc[i+1]}
+	}
+	return band
+}
+
+// Ring creates a full ring as an ArcBand.
+func Ring(center pixel.Vec, inner, outer float64, segments int) []Shape {
+	return ArcBand(center, inner, outer, 0, 2*math.Pi, segments)
+}
+
+// RoundedRect creates a rectangle with corners rounded to radius, using
+// segments lines per corner.
+func RoundedRect(r pixel.Rect, radius float64, segments int) Shape {
+	radius = math.Min(radius, math.Min(r.W(), r.H())/2)
+	if radius <= 0 || segments < 1 {
+		return Shape{r.Min, pixel.V(r.Max.X, r.Min.Y), r.Max, pixel.V(r.Min.X, r.Max.Y)}
+	}
+	corners := []struct {
+		center pixel.Vec
+		start  float64
+	}{
+		{pixel.V(r.Max.X-radius, r.Min.Y+radius), -math.Pi / 2},
+		{pixel.V(r.Max.X-radius, r.Max.Y-radius), 0},
+		{pixel.V(r.Min.X+radius, r.Max.Y-radius), math.Pi / 2},
+		{pixel.V(r.Min.X+radius, r.Min.Y+radius), math.Pi},
+	}
+	s := make(Shape, 0, 4*(segments+1))
+	for _, c := range corners {
+		s = append(s, Arc(c.center, radius, c.start, c.start+math.Pi/2, segments)...)
+	}
+	return s
+}
+
+// Capsule creates the outline of a line from a to b with round ends, as used
+// for rounded character colliders.
+func Capsule(a, b pixel.Vec, radius float64, segments int) Shape {
+	angle := b.Sub(a).Angle()
+	s := Arc(b, radius, angle-math.Pi/2, angle+math.Pi/2, segments)
+	return append(s, Arc(a, radius, angle+math.Pi/2, angle+3*math.Pi/2, segments)...)
+}
+
+// QuadraticBezier creates an open curve from p0 to p2 pulled towards p1.
+func QuadraticBezier(p0, p1, p2 pixel.Vec, segments int) Shape {
+	s := make(Shape, segments+1)
+	for i := range s {
+		t := float64(i) / float64(segments)
+		u := 1 - t
+		s[i] = p0.Scaled(u * u).Add(p1.Scaled(2 * u * t)).Add(p2.Scaled(t * t))
+	}
+	return s
+}
+
+// CubicBezier creates an open curve from p0 to p3 with control points p1 and
+// p2.
+func CubicBezier(p0, p1, p2, p3 pixel.Vec, segments int) Shape {
+	s := make(Shape, segments+1)
+	for i := range s {
+		t := float64(i) / float64(segments)
+		u := 1 - t
+		s[i] = p0.Scaled(u * u * u).
+			Add(p1.Scaled(3 * u * u * t)).
+			Add(p2.Scaled(3 * u * t * t)).
+			Add(p3.Scaled(t * t * t))
+	}
+	return s
+}
+
+// BezierOutline creates a closed shape from cubic Bézier segments. points
+// holds an anchor followed by two control points for each segment, so the
+// segment from anchor i to the next anchor, wrapping around to the first, is
+// points[3i:3i+3] plus that anchor.
+func BezierOutline(points []pixel.Vec, segments int) Shape {
+	n := len(points) / 3
+	s := make(Shape, 0, n*segments)
+	for i := 0; i < n; i++ {
+		next := points[(3*i+3)%(3*n)]
+		curve := CubicBezier(points[3*i], points[3*i+1], points[3*i+2], next, segments)
+		// each curve's last point is the next curve's first
+		s = append(s, curve[:segments]...)
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// Shape is a list of points. Closed shapes are outlines of simple polygons,
+// with the last point joining back to the first. Open shapes, such as arcs
+// and curves, are polylines.
+type Shape []pixel.Vec
+
+// Transformed returns the shape with every point projected by m.
+func (s Shape) Transformed(m pixel.Matrix) Shape {
+	out := make(Shape, len(s))
+	for i, p := range s {
+		out[i] = m.Project(p)
+	}
+	return out
+}
+
+func (s Shape) Moved(delta pixel.Vec) Shape {
+	return s.Transformed(pixel.IM.Moved(delta))
+}
+
+// Rotated rotates the shape around a point by angle radians.
+func (s Shape) Rotated(around pixel.Vec, angle float64) Shape {
+	return s.Transformed(pixel.IM.Rotated(around, angle))
+}
+
+func (s Shape) Scaled(around pixel.Vec, scale float64) Shape {
+	return s.Transformed(pixel.IM.Scaled(around, scale))
+}
+
+func (s Shape) ScaledXY(around pixel.Vec, scale pixel.Vec) Shape {
+	return s.Transformed(pixel.IM.ScaledXY(around, scale))
+}
+
+// Reversed returns the shape with its points in the opposite order, which
+// flips its winding.
+func (s Shape) Reversed() Shape {
+	out := make(Shape, len(s))
+	for i, p := range s {
+		out[len(s)-1-i] = p
+	}
+	return out
+}
+
+// Bounds returns the smallest rectangle containing every point.
+func (s Shape) Bounds() pixel.Rect {
+	if len(s) == 0 {
+		return pixel.Rect{}
+	}
+	r := pixel.Rect{Min: s[0], Max: s[0]}
+	for _, p := range s[1:] {
+		r.Min.X = math.Min(r.Min.X, p.X)
+		r.Min.Y = math.Min(r.Min.Y, p.Y)
+		r.Max.X = math.Max(r.Max.X, p.X)
+		r.Max.Y = math.Max(r.Max.Y, p.Y)
+	}
+	return r
+}
+
+func (s Shape) Area() float64 {
+	return games.PolygonArea(s)
+}
+
+func (s Shape) Centroid() pixel.Vec {
+	return games.PolygonCentroid(s)
+}
+
+// Contains reports whether p is inside the closed shape.
+func (s Shape) Contains(p pixel.Vec) bool {
+	return games.PointInPolygon(p, s)
+}
+
+// Fill draws the closed shape as filled triangles, which unlike
+// imd.Polygon(0) also works for concave shapes.
+func (s Shape) Fill(imd *imdraw.IMDraw) {
+	tris := s.Triangles()
+	for i := 0; i+2 < len(tris); i += 3 {
+		imd.Push(tris[i], tris[i+1], tris[i+2])
+		imd.Polygon(0)
+	}
+}
+
+// Stroke draws the outline of the closed shape.
+func (s Shape) Stroke(imd *imdraw.IMDraw, thickness float64) {
+	imd.Push(s...)
+	imd.Polygon(thickness)
+}
+
+// Line draws the open shape as a polyline.
+func (s Shape) Line(imd *imdraw.IMDraw, thickness float64) {
+	imd.Push(s...)
+	imd.Line(thickness)
+}
@@ -0,0 +1,70 @@
+package shapes
+
+import (
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+)
+
+func cross(o, a, b pixel.Vec) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// Triangles splits the closed shape into triangles by ear clipping and
+// returns their corners, three points per triangle. The shape must not
+// cross itself.
+func (s Shape) Triangles() []pixel.Vec {
+	if len(s) < 3 {
+		return nil
+	}
+
+	// work counter-clockwise so that convex corners have a positive cross
+	indexes := make([]int, len(s))
+	ccw := games.SignedPolygonArea(s) >= 0
+	for i := range indexes {
+		if ccw {
+			indexes[i] = i
+		} else {
+			indexes[i] = len(s) - 1 - i
+		}
+	}
+
+	tris := make([]pixel.Vec, 0, 3*(len(s)-2))
+	for len(indexes) > 3 {
+		ear := -1
+		for i := range indexes {
+			if s.isEar(indexes, i) {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			// not a simple polygon, fan what is left rather than loop forever
+			for i := 1; i+1 < len(indexes); i++ {
+				tris = append(tris, s[indexes[0]], s[indexes[i]], s[indexes[i+1]])
+			}
+			return tris
+		}
+		prev, next := (ear+len(indexes)-1)%len(indexes), (ear+1)%len(indexes)
+		tris = append(tris, s[indexes[prev]], s[indexes[ear]], s[indexes[next]])
+		indexes = append(indexes[:ear], indexes[ear+1:]...)
+	}
+	return append(tris, s[indexes[0]], s[indexes[1]], s[indexes[2]])
+}
+
+func (s Shape) isEar(indexes []int, i int) bool {
+	n := len(indexes)
+	a, b, c := s[indexes[(i+n-1)%n]], s[indexes[i]], s[indexes[(i+1)%n]]
+	if cross(a, b, c) <= 0 {
+		return false
+	}
+	for _, j := range indexes {
+		p := s[j]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if cross(a, b, p) >= 0 && cross(b, c, p) >= 0 && cross(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
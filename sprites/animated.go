@@ -0,0 +1,159 @@
+package sprites
+
+import (
+	"fmt"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+var _ games.Updater = &AnimatedSprite{}
+var _ games.Drawer = &AnimatedSprite{}
+
+// AnimatedSprite plays clips from a sheet.
+//
+// Draw renders into an IMDraw, which must have been created with the sheet's
+// picture, e.g. imdraw.New(sheet.Picture()). DrawTo renders into any target.
+type AnimatedSprite struct {
+	// Matrix places the sprite, which is centered on the origin.
+	Matrix pixel.Matrix
+	// OnEvent is called with the events of each frame as it is shown.
+	OnEvent func(event string)
+
+	sheet    *Sheet
+	sprite   *pixel.Sprite
+	clip     *Clip
+	index    int
+	elapsed  float64
+	backward bool
+	finished bool
+	paused   bool
+}
+
+// NewAnimatedSprite creates a sprite that plays the named clip from sheet.
+func NewAnimatedSprite(sheet *Sheet, clipName string) (*AnimatedSprite, error) {
+	s := &AnimatedSprite{
+		Matrix: pixel.IM,
+		sheet:  sheet,
+		sprite: pixel.NewSprite(sheet.Picture(), pixel.Rect{}),
+	}
+	if err := s.Play(clipName); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Play switches to the named clip and starts it from the beginning. Playing the
+// clip that is already running restarts it.
+func (s *AnimatedSprite) Play(clipName string) error {
+	clip, ok := s.sheet.Clip(clipName)
+	if !ok {
+		return fmt.Errorf("no clip named %q", clipName)
+	}
+	if len(clip.Frames) == 0 {
+		return fmt.Errorf("clip %q has no frames", clipName)
+	}
+	s.clip = clip
+	s.index = 0
+	s.elapsed = 0
+	s.backward = false
+	s.finished = false
+	s.paused = false
+	s.showFrame()
+	return nil
+}
+
+// Clip returns the name of the current clip.
+func (s *AnimatedSprite) Clip() string {
+	return s.clip.Name
+}
+
+// Frame returns the name of the frame being shown.
+func (s *AnimatedSprite) Frame() string {
+	return s.clip.Frames[s.index].Name
+}
+
+// Finished reports whether a Once clip has reached its last frame.
+func (s *AnimatedSprite) Finished() bool {
+	return s.finished
+}
+
+// SetPaused stops or resumes advancing frames.
+func (s *AnimatedSprite) SetPaused(paused bool) {
+	s.paused = paused
+}
+
+func (s *AnimatedSprite) Update(dt float64) {
+	if s.paused || s.finished {
+		return
+	}
+	s.elapsed += dt
+	for !s.finished && s.elapsed >= s.clip.Frames[s.index].Duration {
+		duration := s.clip.Frames[s.index].Duration
+		if duration <= 0 {
+			// a zero-length frame would spin forever
+			s.elapsed = 0
+		} else {
+			s.elapsed -= duration
+		}
+		s.advance()
+	}
+}
+
+func (s *AnimatedSprite) advance() {
+	last := len(s.clip.Frames) - 1
+	switch s.clip.Mode {
+	case Loop:
+		s.index = (s.index + 1) % len(s.clip.Frames)
+	case Once:
+		if s.index == last {
+			s.finished = true
+			s.fire(ClipFinished)
+			return
+		}
+		s.index++
+	case PingPong:
+		if last == 0 {
+			break
+		}
+		if s.backward && s.index == 0 || !s.backward && s.index == last {
+			s.backward = !s.backward
+		}
+		if s.backward {
+			s.index--
+		} else {
+			s.index++
+		}
+	}
+	s.showFrame()
+}
+
+func (s *AnimatedSprite) showFrame() {
+	frame := s.clip.Frames[s.index]
+	bounds, _ := s.sheet.Frame(frame.Name)
+	s.sprite.Set(s.sheet.Picture(), bounds)
+	for _, event := range frame.Events {
+		s.fire(event)
+	}
+}
+
+func (s *AnimatedSprite) fire(event string) {
+	if s.OnEvent != nil {
+		s.OnEvent(event)
+	}
+}
+
+func (s *AnimatedSprite) Draw(imd *imdraw.IMDraw) {
+	s.DrawTo(imd)
+}
+
+// DrawTo draws the current frame into t.
+func (s *AnimatedSprite) DrawTo(t pixel.Target) {
+	s.sprite.Draw(t, s.Matrix)
+}
+
+// FrameSize returns the size of the frame being shown.
+func (s *AnimatedSprite) FrameSize() pixel.Vec {
+	return s.sprite.Frame().Size()
+}
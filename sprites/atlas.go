@@ -0,0 +1,131 @@
+package sprites
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+// defaultFrameDuration is used for atlas frames that do not specify one.
+const defaultFrameDuration = 0.1
+
+type atlasRect struct {
+	X, Y, W, H float64
+}
+
+type atlasFrame struct {
+	Filename string    `json:"filename"`
+	Frame    atlasRect `json:"frame"`
+	Duration float64   `json:"duration"` // milliseconds
+}
+
+type atlasTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+	Repeat    string `json:"repeat"`
+}
+
+type atlasFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		FrameTags []atlasTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// ParseAtlas reads a JSON texture atlas as exported by TexturePacker or
+// Aseprite. Frames may be given as an array or as an object keyed by name.
+// Aseprite frame tags become clips, using the per-frame durations.
+func ParseAtlas(picture pixel.Picture, data []byte) (*Sheet, error) {
+	var file atlasFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to parse atlas: %v", err)
+	}
+	frames, err := parseAtlasFrames(file.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse atlas frames: %v", err)
+	}
+
+	sheet := NewSheet(picture)
+	height := picture.Bounds().H()
+	for _, f := range frames {
+		// atlases are authored top-down, pictures are y-up
+		y := height - f.Frame.Y - f.Frame.H
+		sheet.AddFrame(f.Filename, pixel.R(f.Frame.X, y, f.Frame.X+f.Frame.W, y+f.Frame.H))
+	}
+
+	for _, tag := range file.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("tag %q has invalid frame range %d-%d", tag.Name, tag.From, tag.To)
+		}
+		clip := &Clip{Name: tag.Name}
+		for i := tag.From; i <= tag.To; i++ {
+			clip.Frames = append(clip.Frames, Frame{
+				Name:     frames[i].Filename,
+				Duration: frameDuration(frames[i]),
+			})
+		}
+		switch tag.Direction {
+		case "reverse":
+			for i, j := 0, len(clip.Frames)-1; i < j; i, j = i+1, j-1 {
+				clip.Frames[i], clip.Frames[j] = clip.Frames[j], clip.Frames[i]
+			}
+		case "pingpong":
+			clip.Mode = PingPong
+		}
+		if tag.Repeat == "1" {
+			clip.Mode = Once
+		}
+		if err := sheet.AddClip(clip); err != nil {
+			return nil, err
+		}
+	}
+
+	return sheet, nil
+}
+
+func frameDuration(f atlasFrame) float64 {
+	if f.Duration <= 0 {
+		return defaultFrameDuration
+	}
+	return f.Duration / 1000
+}
+
+// parseAtlasFrames reads frames from either an array or an object, keeping
+// the order of an object's keys so that tag ranges line up.
+func parseAtlasFrames(raw json.RawMessage) ([]atlasFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no frames")
+	}
+
+	if raw[0] == '[' {
+		var frames []atlasFrame
+		if err := json.Unmarshal(raw, &frames); err != nil {
+			return nil, err
+		}
+		return frames, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var frames []atlasFrame
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var f atlasFrame
+		if err := decoder.Decode(&f); err != nil {
+			return nil, err
+		}
+		f.Filename = token.(string)
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
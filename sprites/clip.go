@@ -0,0 +1,69 @@
+package sprites
+
+import "strconv"
+
+// LoopMode controls what a clip does when it runs out of frames.
+type LoopMode int
+
+const (
+	// Loop restarts the clip from the first frame.
+	Loop LoopMode = iota
+	// Once stops on the last frame.
+	Once
+	// PingPong plays the clip backwards, then forwards again.
+	PingPong
+)
+
+// ClipFinished is the event fired when a Once clip reaches its end.
+const ClipFinished = "finished"
+
+// Frame is a single step of a clip.
+type Frame struct {
+	Name     string
+	Duration float64 // seconds
+	Events   []string
+}
+
+// Clip is a named sequence of frames.
+type Clip struct {
+	Name   string
+	Frames []Frame
+	Mode   LoopMode
+}
+
+// NewClip creates a clip that shows each named frame for the same duration.
+func NewClip(name string, mode LoopMode, frameDuration float64, frameNames ...string) *Clip {
+	clip := &Clip{
+		Name: name,
+		Mode: mode,
+	}
+	for _, frameName := range frameNames {
+		clip.Frames = append(clip.Frames, Frame{Name: frameName, Duration: frameDuration})
+	}
+	return clip
+}
+
+// NewRangeClip creates a clip over the grid frames first through last inclusive,
+// as named by NewGridSheet.
+func NewRangeClip(name string, mode LoopMode, frameDuration float64, first, last int) *Clip {
+	var names []string
+	for i := first; i <= last; i++ {
+		names = append(names, strconv.Itoa(i))
+	}
+	return NewClip(name, mode, frameDuration, names...)
+}
+
+// On attaches an event to the frame at index, fired each time the frame is shown.
+func (c *Clip) On(index int, event string) *Clip {
+	c.Frames[index].Events = append(c.Frames[index].Events, event)
+	return c
+}
+
+// Duration returns the length of a single pass through the clip.
+func (c *Clip) Duration() float64 {
+	total := 0.0
+	for _, frame := range c.Frames {
+		total += frame.Duration
+	}
+	return total
+}
@@ -0,0 +1,101 @@
+package sprites
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/faiface/pixel"
+)
+
+// Sheet is a picture cut into named frames.
+type Sheet struct {
+	picture pixel.Picture
+	names   []string
+	frames  map[string]pixel.Rect
+	clips   map[string]*Clip
+}
+
+// NewSheet creates an empty sheet over picture. Frames are added with AddFrame.
+func NewSheet(picture pixel.Picture) *Sheet {
+	return &Sheet{
+		picture: picture,
+		frames:  make(map[string]pixel.Rect),
+		clips:   make(map[string]*Clip),
+	}
+}
+
+// NewGridSheet cuts picture into frames of frameWidth by frameHeight, read left
+// to right and top to bottom. The frames are named "0", "1", "2"...
+func NewGridSheet(picture pixel.Picture, frameWidth, frameHeight float64) (*Sheet, error) {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return nil, fmt.Errorf("invalid frame size %vx%v", frameWidth, frameHeight)
+	}
+	bounds := picture.Bounds()
+	columns := int(bounds.W() / frameWidth)
+	rows := int(bounds.H() / frameHeight)
+	if columns == 0 || rows == 0 {
+		return nil, fmt.Errorf("frame size %vx%v is larger than the picture", frameWidth, frameHeight)
+	}
+
+	sheet := NewSheet(picture)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			x := bounds.Min.X + float64(column)*frameWidth
+			// pictures are y-up, sheets are authored top-down
+			y := bounds.Max.Y - float64(row+1)*frameHeight
+			sheet.AddFrame(strconv.Itoa(row*columns+column), pixel.R(x, y, x+frameWidth, y+frameHeight))
+		}
+	}
+	return sheet, nil
+}
+
+// Picture returns the underlying picture of the sheet.
+func (s *Sheet) Picture() pixel.Picture {
+	return s.picture
+}
+
+// AddFrame adds or replaces a named frame.
+func (s *Sheet) AddFrame(name string, frame pixel.Rect) {
+	if _, ok := s.frames[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.frames[name] = frame
+}
+
+// Frame returns the bounds of a named frame.
+func (s *Sheet) Frame(name string) (pixel.Rect, bool) {
+	frame, ok := s.frames[name]
+	return frame, ok
+}
+
+// FrameNames returns the frame names in the order they were added.
+func (s *Sheet) FrameNames() []string {
+	return append([]string(nil), s.names...)
+}
+
+// Sprite creates a sprite showing a named frame.
+func (s *Sheet) Sprite(name string) (*pixel.Sprite, error) {
+	frame, ok := s.frames[name]
+	if !ok {
+		return nil, fmt.Errorf("no frame named %q", name)
+	}
+	return pixel.NewSprite(s.picture, frame), nil
+}
+
+// AddClip registers an animation clip with the sheet. Every frame of the clip
+// must exist in the sheet.
+func (s *Sheet) AddClip(clip *Clip) error {
+	for _, frame := range clip.Frames {
+		if _, ok := s.frames[frame.Name]; !ok {
+			return fmt.Errorf("clip %q uses unknown frame %q", clip.Name, frame.Name)
+		}
+	}
+	s.clips[clip.Name] = clip
+	return nil
+}
+
+// Clip returns a registered animation clip.
+func (s *Sheet) Clip(name string) (*Clip, bool) {
+	clip, ok := s.clips[name]
+	return clip, ok
+}
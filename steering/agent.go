@@ -0,0 +1,153 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+)
+
+var _ games.Updater = Agent{}
+
+// Agent steers a physics body. Behaviours return the force that turns the
+// body's current velocity towards the velocity it wants, limited by
+// MaxForce; combine them with Blend and apply the result with Apply.
+type Agent struct {
+	*games.Physics
+	MaxSpeed float64
+	MaxForce float64
+}
+
+// Apply adds a steering force to the body, limited to MaxForce.
+func (a Agent) Apply(force pixel.Vec) {
+	force = Truncate(force, a.MaxForce)
+	a.Force(force.X, force.Y)
+}
+
+// Update moves the body and keeps it below MaxSpeed.
+func (a Agent) Update(dt float64) {
+	a.Physics.Update(dt)
+	a.Velocity = Truncate(a.Velocity, a.MaxSpeed)
+}
+
+// Heading is the unit direction of travel, or zero when standing still.
+func (a Agent) Heading() pixel.Vec {
+	if a.Velocity == pixel.ZV {
+		return pixel.ZV
+	}
+	return a.Velocity.Unit()
+}
+
+// steer returns the force that changes the velocity to desired.
+func (a Agent) steer(desired pixel.Vec) pixel.Vec {
+	return Truncate(desired.Sub(a.Velocity), a.MaxForce)
+}
+
+// Truncate shortens v to at most max.
+func Truncate(v pixel.Vec, max float64) pixel.Vec {
+	if length := v.Len(); length > max && length > 0 {
+		return v.Scaled(max / length)
+	}
+	return v
+}
+
+// Seek heads straight for target at full speed.
+func (a Agent) Seek(target pixel.Vec) pixel.Vec {
+	to := target.Sub(a.Position)
+	if to == pixel.ZV {
+		return a.steer(pixel.ZV)
+	}
+	return a.steer(to.Unit().Scaled(a.MaxSpeed))
+}
+
+// Flee runs directly away from threat while it is within panicDistance. A
+// panicDistance of zero or less always flees.
+func (a Agent) Flee(threat pixel.Vec, panicDistance float64) pixel.Vec {
+	away := a.Position.Sub(threat)
+	if panicDistance > 0 && away.Len() > panicDistance {
+		return pixel.ZV
+	}
+	if away == pixel.ZV {
+		away = pixel.V(1, 0)
+	}
+	return a.steer(away.Unit().Scaled(a.MaxSpeed))
+}
+
+// Arrive seeks target but slows down within slowingRadius so that it stops
+// on the target instead of overshooting.
+func (a Agent) Arrive(target pixel.Vec, slowingRadius float64) pixel.Vec {
+	to := target.Sub(a.Position)
+	distance := to.Len()
+	if distance == 0 {
+		return a.steer(pixel.ZV)
+	}
+	speed := a.MaxSpeed
+	if distance < slowingRadius {
+		speed *= distance / slowingRadius
+	}
+	return a.steer(to.Scaled(speed / distance))
+}
+
+// predict guesses where quarry will be by the time a reaches it.
+func (a Agent) predict(quarry *games.Physics) pixel.Vec {
+	distance := games.Distance(a.Position, quarry.Position)
+	speed := a.MaxSpeed + quarry.Velocity.Len()
+	if speed == 0 {
+		return quarry.Position
+	}
+	return quarry.Position.Add(quarry.Velocity.Scaled(distance / speed))
+}
+
+// Pursue seeks where quarry is heading rather than where it is.
+func (a Agent) Pursue(quarry *games.Physics) pixel.Vec {
+	return a.Seek(a.predict(quarry))
+}
+
+// Evade flees from where threat is heading.
+func (a Agent) Evade(threat *games.Physics, panicDistance float64) pixel.Vec {
+	if panicDistance > 0 && games.Distance(a.Position, threat.Position) > panicDistance {
+		return pixel.ZV
+	}
+	return a.Flee(a.predict(threat), 0)
+}
+
+// Weighted is a behaviour's force and how much it counts in a blend.
+type Weighted struct {
+	Force  pixel.Vec
+	Weight float64
+}
+
+// Blend sums weighted forces and limits the result to maxForce.
+func Blend(maxForce float64, forces ...Weighted) pixel.Vec {
+	total := pixel.ZV
+	for _, f := range forces {
+		total = total.Add(f.Force.Scaled(f.Weight))
+	}
+	return Truncate(total, maxForce)
+}
+
+// Prioritize adds weighted forces in order until maxForce is used up, so
+// that urgent behaviours such as avoiding obstacles listed first win over
+// the rest.
+func Prioritize(maxForce float64, forces ...Weighted) pixel.Vec {
+	total := pixel.ZV
+	remaining := maxForce
+	for _, f := range forces {
+		force := f.Force.Scaled(f.Weight)
+		length := force.Len()
+		if length >= remaining {
+			return total.Add(Truncate(force, remaining))
+		}
+		total = total.Add(force)
+		remaining -= length
+	}
+	return total
+}
+
+// angleBetween is the unsigned angle between two directions.
+func angleBetween(u, v pixel.Vec) float64 {
+	if u == pixel.ZV || v == pixel.ZV {
+		return 0
+	}
+	return math.Abs(games.AngleDifference(u.Angle(), v.Angle()))
+}
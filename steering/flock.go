@@ -0,0 +1,103 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+)
+
+// Neighbours returns the bodies within radius of the agent and inside its
+// field of view, an angle in radians either side of its heading. A field of
+// view of π or more sees all around. The agent's own body is skipped.
+func (a Agent) Neighbours(bodies []*games.Physics, radius, fieldOfView float64) []*games.Physics {
+	heading := a.Heading()
+	var near []*games.Physics
+	for _, b := range bodies {
+		if b == a.Physics {
+			continue
+		}
+		to := b.Position.Sub(a.Position)
+		if to.Len() > radius {
+			continue
+		}
+		if fieldOfView < math.Pi && heading != pixel.ZV && angleBetween(heading, to) > fieldOfView {
+			continue
+		}
+		near = append(near, b)
+	}
+	return near
+}
+
+// Separation pushes away from neighbours, harder the closer they are.
+func (a Agent) Separation(neighbours []*games.Physics) pixel.Vec {
+	total := pixel.ZV
+	for _, n := range neighbours {
+		away := a.Position.Sub(n.Position)
+		if d := away.Len(); d > 0 {
+			total = total.Add(away.Scaled(1 / (d * d)))
+		}
+	}
+	if total == pixel.ZV {
+		return pixel.ZV
+	}
+	return a.steer(total.Unit().Scaled(a.MaxSpeed))
+}
+
+// Alignment steers towards the average heading of neighbours.
+func (a Agent) Alignment(neighbours []*games.Physics) pixel.Vec {
+	if len(neighbours) == 0 {
+		return pixel.ZV
+	}
+	total := pixel.ZV
+	for _, n := range neighbours {
+		total = total.Add(n.Velocity)
+	}
+	if total == pixel.ZV {
+		return pixel.ZV
+	}
+	return a.steer(total.Unit().Scaled(a.MaxSpeed))
+}
+
+// Cohesion seeks the center of neighbours.
+func (a Agent) Cohesion(neighbours []*games.Physics) pixel.Vec {
+	if len(neighbours) == 0 {
+		return pixel.ZV
+	}
+	center := pixel.ZV
+	for _, n := range neighbours {
+		center = center.Add(n.Position)
+	}
+	return a.Seek(center.Scaled(1 / float64(len(neighbours))))
+}
+
+// Flock weights separation, alignment and cohesion.
+type Flock struct {
+	Radius      float64
+	FieldOfView float64
+	Separation  float64
+	Alignment   float64
+	Cohesion    float64
+}
+
+// DefaultFlock gives loose, natural looking flocks for agents of about the
+// given radius apart.
+func DefaultFlock(radius float64) Flock {
+	return Flock{
+		Radius:      radius,
+		FieldOfView: 3 * math.Pi / 4,
+		Separation:  1.5,
+		Alignment:   1,
+		Cohesion:    1,
+	}
+}
+
+// Force blends the flocking behaviours of a among bodies.
+func (f Flock) Force(a Agent, bodies []*games.Physics) pixel.Vec {
+	neighbours := a.Neighbours(bodies, f.Radius, f.FieldOfView)
+	return Blend(a.MaxForce,
+		Weighted{Force: a.Separation(neighbours), Weight: f.Separation},
+		Weighted{Force: a.Alignment(neighbours), Weight: f.Alignment},
+		Weighted{Force: a.Cohesion(neighbours), Weight: f.Cohesion},
+	)
+}
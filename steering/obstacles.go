@@ -0,0 +1,120 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/explodes/gogames"
+	"github.com/faiface/pixel"
+)
+
+// Obstacle is a circle to steer around.
+type Obstacle struct {
+	Center pixel.Vec
+	Radius float64
+}
+
+// Wall is a line segment. Its normal, which points towards the side agents
+// should stay on, is to the left when walking from A to B.
+type Wall struct {
+	A, B pixel.Vec
+}
+
+func (w Wall) Normal() pixel.Vec {
+	return w.B.Sub(w.A).Unit().Normal()
+}
+
+// AvoidObstacles looks lookAhead seconds ahead along the agent's velocity
+// and pushes sideways away from the nearest obstacle in its path. radius is
+// the agent's own size.
+func (a Agent) AvoidObstacles(obstacles []Obstacle, radius, lookAhead float64) pixel.Vec {
+	heading := a.Heading()
+	if heading == pixel.ZV {
+		return pixel.ZV
+	}
+	reach := a.Velocity.Len() * lookAhead
+	side := heading.Normal()
+
+	var nearest *Obstacle
+	nearestAhead, nearestSide := math.Inf(1), 0.0
+	for i := range obstacles {
+		o := &obstacles[i]
+		to := o.Center.Sub(a.Position)
+		ahead := to.Dot(heading)
+		clearance := o.Radius + radius
+		if ahead < -clearance || ahead > reach+clearance {
+			continue
+		}
+		lateral := to.Dot(side)
+		if math.Abs(lateral) >= clearance {
+			continue
+		}
+		if ahead < nearestAhead {
+			nearest, nearestAhead, nearestSide = o, ahead, lateral
+		}
+	}
+	if nearest == nil {
+		return pixel.ZV
+	}
+
+	// push harder the closer and more head-on the obstacle is
+	clearance := nearest.Radius + radius
+	urgency := 1 + (reach-math.Max(0, nearestAhead))/math.Max(reach, 1)
+	direction := -1.0
+	if nearestSide < 0 {
+		direction = 1
+	}
+	push := side.Scaled(direction * (clearance - math.Abs(nearestSide)) / clearance * a.MaxForce * urgency)
+	return Truncate(push, a.MaxForce)
+}
+
+// nearestWall returns the wall closest to p within maxDistance.
+func nearestWall(walls []Wall, p pixel.Vec, maxDistance float64) (Wall, pixel.Vec, bool) {
+	var found Wall
+	var closest pixel.Vec
+	best := maxDistance
+	ok := false
+	for _, w := range walls {
+		c := games.ClosestPointOnSegment(p, w.A, w.B)
+		if d := games.Distance(p, c); d <= best {
+			found, closest, best, ok = w, c, d, true
+		}
+	}
+	return found, closest, ok
+}
+
+// AvoidWalls pushes away from walls within distance, harder the closer the
+// agent is.
+func (a Agent) AvoidWalls(walls []Wall, distance float64) pixel.Vec {
+	total := pixel.ZV
+	for _, w := range walls {
+		c := games.ClosestPointOnSegment(a.Position, w.A, w.B)
+		away := a.Position.Sub(c)
+		d := away.Len()
+		if d >= distance || d == 0 {
+			continue
+		}
+		total = total.Add(away.Scaled((distance - d) / (d * distance) * a.MaxForce))
+	}
+	return Truncate(total, a.MaxForce)
+}
+
+// FollowWall travels along the nearest wall within reach, keeping offset
+// away from it on the side the agent is on, in the direction the agent is
+// already heading.
+func (a Agent) FollowWall(walls []Wall, offset, reach float64) pixel.Vec {
+	w, closest, ok := nearestWall(walls, a.Position, reach)
+	if !ok {
+		return pixel.ZV
+	}
+	tangent := w.B.Sub(w.A).Unit()
+	if a.Velocity.Dot(tangent) < 0 {
+		tangent = tangent.Scaled(-1)
+	}
+	normal := w.Normal()
+	if a.Position.Sub(closest).Dot(normal) < 0 {
+		normal = normal.Scaled(-1)
+	}
+	lead := math.Max(offset, a.Velocity.Len()*0.5)
+	target := closest.Add(normal.Scaled(offset)).Add(tangent.Scaled(lead))
+	return a.Seek(target)
+}
@@ -0,0 +1,44 @@
+package steering
+
+import (
+	"github.com/explodes/gogames"
+	"github.com/explodes/gogames/rng"
+	"github.com/faiface/pixel"
+)
+
+// Wander produces a smoothly changing random walk by seeking a point that
+// drifts around a circle projected in front of the agent.
+type Wander struct {
+	// Distance is how far ahead of the agent the circle is.
+	Distance float64
+	// Radius is the size of the circle, larger makes sharper turns.
+	Radius float64
+	// Jitter is how far in radians per second the target moves around the
+	// circle.
+	Jitter float64
+
+	rng   *rng.RNG
+	angle float64
+}
+
+func NewWander(rng *rng.RNG, distance, radius, jitter float64) *Wander {
+	return &Wander{
+		Distance: distance,
+		Radius:   radius,
+		Jitter:   jitter,
+		rng:      rng,
+		angle:    rng.Angle(),
+	}
+}
+
+// Force moves the wander target and returns the force towards it.
+func (w *Wander) Force(a Agent, dt float64) pixel.Vec {
+	w.angle = games.WrapAngle(w.angle + w.rng.Range(-1, 1)*w.Jitter*dt)
+	heading := a.Heading()
+	if heading == pixel.ZV {
+		heading = pixel.V(1, 0)
+	}
+	center := a.Position.Add(heading.Scaled(w.Distance))
+	target := center.Add(pixel.Unit(heading.Angle() + w.angle).Scaled(w.Radius))
+	return a.Seek(target)
+}
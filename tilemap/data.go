@@ -0,0 +1,79 @@
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// decodeBase64 decodes base64 layer data with optional gzip or zlib
+// compression into little-endian global tile IDs.
+func decodeBase64(data, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 layer data: %v", err)
+	}
+
+	var r io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "gzip":
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("invalid gzip layer data: %v", err)
+		}
+	case "zlib":
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, fmt.Errorf("invalid zlib layer data: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported layer compression %q", compression)
+	}
+
+	if raw, err = ioutil.ReadAll(r); err != nil {
+		return nil, fmt.Errorf("unable to decompress layer data: %v", err)
+	}
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("layer data length %d is not a multiple of 4", len(raw))
+	}
+
+	tiles := make([]uint32, len(raw)/4)
+	for i := range tiles {
+		tiles[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return tiles, nil
+}
+
+// decodeCSV decodes comma separated global tile IDs.
+func decodeCSV(data string) ([]uint32, error) {
+	fields := strings.FieldsFunc(data, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == ' ' || r == '\t'
+	})
+	tiles := make([]uint32, len(fields))
+	for i, field := range fields {
+		tile, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv layer data: %v", err)
+		}
+		tiles[i] = uint32(tile)
+	}
+	return tiles, nil
+}
+
+// parseProperty converts a Tiled property value of the given type.
+func parseProperty(kind, value string) (interface{}, error) {
+	switch kind {
+	case "int", "float":
+		return strconv.ParseFloat(value, 64)
+	case "bool":
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
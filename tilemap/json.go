@@ -0,0 +1,308 @@
+package tilemap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+type jsonProperty struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type jsonProperties json.RawMessage
+
+func (p *jsonProperties) UnmarshalJSON(data []byte) error {
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+type jsonTile struct {
+	ID         uint32         `json:"id"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonTileset struct {
+	FirstGID   uint32         `json:"firstgid"`
+	Source     string         `json:"source"`
+	Name       string         `json:"name"`
+	TileWidth  int            `json:"tilewidth"`
+	TileHeight int            `json:"tileheight"`
+	Spacing    int            `json:"spacing"`
+	Margin     int            `json:"margin"`
+	TileCount  int            `json:"tilecount"`
+	Columns    int            `json:"columns"`
+	Image      string         `json:"image"`
+	Tiles      []jsonTile     `json:"tiles"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonPoint struct {
+	X, Y float64
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Rotation   float64        `json:"rotation"`
+	GID        uint32         `json:"gid"`
+	Visible    bool           `json:"visible"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Polygon    []jsonPoint    `json:"polygon"`
+	Polyline   []jsonPoint    `json:"polyline"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Visible     bool            `json:"visible"`
+	Opacity     float64         `json:"opacity"`
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+	Properties  jsonProperties  `json:"properties"`
+}
+
+type jsonMap struct {
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Orientation string         `json:"orientation"`
+	Infinite    bool           `json:"infinite"`
+	Tilesets    []jsonTileset  `json:"tilesets"`
+	Layers      []jsonLayer    `json:"layers"`
+	Properties  jsonProperties `json:"properties"`
+}
+
+// ParseJSON reads a map saved in Tiled's JSON format. Maps that reference
+// external tilesets must be opened with Load.
+func ParseJSON(data []byte) (*Map, error) {
+	return parseJSON(data, "", noExternalFiles)
+}
+
+func parseJSON(data []byte, dir string, readFile fileReader) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, fmt.Errorf("unable to parse map: %v", err)
+	}
+	if jm.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+	if jm.Orientation != "" && jm.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported orientation %q", jm.Orientation)
+	}
+
+	properties, err := jm.Properties.decode()
+	if err != nil {
+		return nil, err
+	}
+	m := &Map{
+		Width:      jm.Width,
+		Height:     jm.Height,
+		TileWidth:  jm.TileWidth,
+		TileHeight: jm.TileHeight,
+		Properties: properties,
+	}
+
+	for _, jt := range jm.Tilesets {
+		if jt.Source != "" {
+			ts, err := readFile.tileset(jt.Source)
+			if err != nil {
+				return nil, err
+			}
+			ts.FirstGID = jt.FirstGID
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts, err := jt.tileset(dir)
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	if err := m.addJSONLayers(jm.Layers); err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseJSONTileset reads an external tileset in Tiled's JSON format. dir is
+// the directory of the tileset file, used to resolve its image.
+func parseJSONTileset(data []byte, dir string) (*Tileset, error) {
+	var jt jsonTileset
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return nil, fmt.Errorf("unable to parse tileset: %v", err)
+	}
+	return jt.tileset(dir)
+}
+
+func (jt *jsonTileset) tileset(dir string) (*Tileset, error) {
+	ts := &Tileset{
+		FirstGID:       jt.FirstGID,
+		Name:           jt.Name,
+		TileWidth:      jt.TileWidth,
+		TileHeight:     jt.TileHeight,
+		Spacing:        jt.Spacing,
+		Margin:         jt.Margin,
+		TileCount:      jt.TileCount,
+		Columns:        jt.Columns,
+		Image:          resolvePath(dir, jt.Image),
+		TileProperties: make(map[uint32]Properties),
+	}
+	for _, tile := range jt.Tiles {
+		properties, err := tile.Properties.decode()
+		if err != nil {
+			return nil, err
+		}
+		if len(properties) > 0 {
+			ts.TileProperties[tile.ID] = properties
+		}
+	}
+	return ts, nil
+}
+
+func (m *Map) addJSONLayers(layers []jsonLayer) error {
+	for _, jl := range layers {
+		properties, err := jl.Properties.decode()
+		if err != nil {
+			return err
+		}
+		switch jl.Type {
+		case "tilelayer":
+			tiles, err := jl.tiles()
+			if err != nil {
+				return fmt.Errorf("layer %q: %v", jl.Name, err)
+			}
+			m.Layers = append(m.Layers, &Layer{
+				Name:       jl.Name,
+				Width:      jl.Width,
+				Height:     jl.Height,
+				Visible:    jl.Visible,
+				Opacity:    jl.Opacity,
+				Properties: properties,
+				Tiles:      tiles,
+			})
+		case "objectgroup":
+			group := &ObjectGroup{
+				Name:       jl.Name,
+				Visible:    jl.Visible,
+				Properties: properties,
+			}
+			for _, jo := range jl.Objects {
+				object, err := jo.object()
+				if err != nil {
+					return err
+				}
+				group.Objects = append(group.Objects, object)
+			}
+			m.ObjectGroups = append(m.ObjectGroups, group)
+		case "group":
+			if err := m.addJSONLayers(jl.Layers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (jl *jsonLayer) tiles() ([]uint32, error) {
+	switch jl.Encoding {
+	case "", "csv":
+		var tiles []uint32
+		if err := json.Unmarshal(jl.Data, &tiles); err != nil {
+			return nil, fmt.Errorf("invalid layer data: %v", err)
+		}
+		return tiles, nil
+	case "base64":
+		var data string
+		if err := json.Unmarshal(jl.Data, &data); err != nil {
+			return nil, fmt.Errorf("invalid layer data: %v", err)
+		}
+		return decodeBase64(data, jl.Compression)
+	default:
+		return nil, fmt.Errorf("unsupported layer encoding %q", jl.Encoding)
+	}
+}
+
+func (jo *jsonObject) object() (*Object, error) {
+	properties, err := jo.Properties.decode()
+	if err != nil {
+		return nil, err
+	}
+	kind := jo.Type
+	if kind == "" {
+		kind = jo.Class
+	}
+	return &Object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Type:       kind,
+		X:          jo.X,
+		Y:          jo.Y,
+		Width:      jo.Width,
+		Height:     jo.Height,
+		Rotation:   jo.Rotation,
+		GID:        jo.GID,
+		Visible:    jo.Visible,
+		Ellipse:    jo.Ellipse,
+		Point:      jo.Point,
+		Polygon:    jsonPoints(jo.Polygon),
+		Polyline:   jsonPoints(jo.Polyline),
+		Properties: properties,
+	}, nil
+}
+
+func jsonPoints(points []jsonPoint) []pixel.Vec {
+	var vecs []pixel.Vec
+	for _, p := range points {
+		vecs = append(vecs, pixel.V(p.X, p.Y))
+	}
+	return vecs
+}
+
+// decode reads properties in either the current array form or the object
+// form written by Tiled before 1.2.
+func (p jsonProperties) decode() (Properties, error) {
+	raw := bytes.TrimSpace(p)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return Properties{}, nil
+	}
+
+	properties := Properties{}
+	if raw[0] == '{' {
+		if err := json.Unmarshal(raw, &properties); err != nil {
+			return nil, fmt.Errorf("invalid properties: %v", err)
+		}
+		return properties, nil
+	}
+
+	var list []jsonProperty
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("invalid properties: %v", err)
+	}
+	for _, property := range list {
+		properties[property.Name] = property.Value
+	}
+	return properties, nil
+}
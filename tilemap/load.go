@@ -0,0 +1,68 @@
+package tilemap
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// fileReader reads files referenced by a map, relative to the map's directory.
+type fileReader func(name string) (data []byte, dir string, err error)
+
+func noExternalFiles(name string) ([]byte, string, error) {
+	return nil, "", fmt.Errorf("external file %s cannot be read, open the map with Load", name)
+}
+
+func (readFile fileReader) tileset(source string) (*Tileset, error) {
+	data, dir, err := readFile(source)
+	if err != nil {
+		return nil, err
+	}
+	if isXML(source) {
+		return parseTSX(data, dir)
+	}
+	return parseJSONTileset(data, dir)
+}
+
+// Load opens a TMX or JSON map from fsys, along with any external tilesets it
+// references. Tileset image paths are resolved relative to the root of fsys.
+func Load(fsys fs.FS, name string) (*Map, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read map %s: %v", name, err)
+	}
+
+	mapDir := path.Dir(name)
+	readFile := func(source string) ([]byte, string, error) {
+		source = resolvePath(mapDir, source)
+		data, err := fs.ReadFile(fsys, source)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to read %s: %v", source, err)
+		}
+		return data, path.Dir(source), nil
+	}
+
+	var m *Map
+	if isXML(name) {
+		m, err = parseTMX(data, mapDir, readFile)
+	} else {
+		m, err = parseJSON(data, mapDir, readFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return m, nil
+}
+
+func isXML(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".tmx" || ext == ".tsx" || ext == ".xml"
+}
+
+func resolvePath(dir, name string) string {
+	if name == "" || dir == "" || path.IsAbs(name) {
+		return name
+	}
+	return path.Join(dir, name)
+}
@@ -0,0 +1,231 @@
+package tilemap
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Tiled stores flip flags in the top bits of a global tile ID.
+const (
+	FlippedHorizontally uint32 = 0x80000000
+	FlippedVertically   uint32 = 0x40000000
+	FlippedDiagonally   uint32 = 0x20000000
+
+	flipMask = FlippedHorizontally | FlippedVertically | FlippedDiagonally
+)
+
+// GID strips the flip flags from a global tile ID. Zero means no tile.
+func GID(tile uint32) uint32 {
+	return tile &^ flipMask
+}
+
+// Properties are the custom properties of a map, layer, tile or object.
+// Values are strings, float64s or bools.
+type Properties map[string]interface{}
+
+func (p Properties) String(name string) string {
+	s, _ := p[name].(string)
+	return s
+}
+
+func (p Properties) Float(name string) float64 {
+	f, _ := p[name].(float64)
+	return f
+}
+
+func (p Properties) Bool(name string) bool {
+	b, _ := p[name].(bool)
+	return b
+}
+
+// Map is a Tiled map. Tile coordinates follow Tiled: (0, 0) is the top-left
+// tile. World coordinates are y-up with (0, 0) at the bottom-left corner.
+type Map struct {
+	Width, Height         int
+	TileWidth, TileHeight int
+	Properties            Properties
+	Tilesets              []*Tileset
+	Layers                []*Layer
+	ObjectGroups          []*ObjectGroup
+}
+
+// Tileset is a set of tiles cut from one image.
+type Tileset struct {
+	FirstGID              uint32
+	Name                  string
+	TileWidth, TileHeight int
+	Spacing, Margin       int
+	TileCount, Columns    int
+	// Image is the path to the tileset image. Maps opened with Load resolve it
+	// relative to the root of their filesystem.
+	Image string
+	// TileProperties are keyed by local tile ID.
+	TileProperties map[uint32]Properties
+}
+
+// Contains reports whether gid belongs to this tileset.
+func (ts *Tileset) Contains(gid uint32) bool {
+	return gid >= ts.FirstGID && gid < ts.FirstGID+uint32(ts.TileCount)
+}
+
+// Frame returns the bounds of a local tile ID within a y-up picture of the
+// given height.
+func (ts *Tileset) Frame(id uint32, pictureHeight float64) pixel.Rect {
+	columns := ts.Columns
+	if columns <= 0 {
+		columns = 1
+	}
+	column := int(id) % columns
+	row := int(id) / columns
+	x := float64(ts.Margin + column*(ts.TileWidth+ts.Spacing))
+	top := float64(ts.Margin + row*(ts.TileHeight+ts.Spacing))
+	y := pictureHeight - top - float64(ts.TileHeight)
+	return pixel.R(x, y, x+float64(ts.TileWidth), y+float64(ts.TileHeight))
+}
+
+// Layer is a grid of global tile IDs, stored row by row from the top.
+type Layer struct {
+	Name          string
+	Width, Height int
+	Visible       bool
+	Opacity       float64
+	Properties    Properties
+	Tiles         []uint32
+}
+
+// Tile returns the raw tile at (x, y), including flip flags, or zero when the
+// coordinates are outside of the layer.
+func (l *Layer) Tile(x, y int) uint32 {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+	return l.Tiles[x+y*l.Width]
+}
+
+// ObjectGroup is a layer of free-form objects.
+type ObjectGroup struct {
+	Name       string
+	Visible    bool
+	Properties Properties
+	Objects    []*Object
+}
+
+// Object is a shape or tile placed on an object layer. Positions are in
+// Tiled's pixel coordinates, y-down.
+type Object struct {
+	ID                int
+	Name, Type        string
+	X, Y              float64
+	Width, Height     float64
+	Rotation          float64
+	GID               uint32
+	Visible           bool
+	Ellipse, Point    bool
+	Polygon, Polyline []pixel.Vec
+	Properties        Properties
+}
+
+// Layer returns the named tile layer.
+func (m *Map) Layer(name string) (*Layer, bool) {
+	for _, layer := range m.Layers {
+		if layer.Name == name {
+			return layer, true
+		}
+	}
+	return nil, false
+}
+
+// ObjectGroup returns the named object layer.
+func (m *Map) ObjectGroup(name string) (*ObjectGroup, bool) {
+	for _, group := range m.ObjectGroups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+	return nil, false
+}
+
+// Tileset returns the tileset containing gid, and the tile's local ID in it.
+func (m *Map) Tileset(gid uint32) (*Tileset, uint32, bool) {
+	gid = GID(gid)
+	for _, ts := range m.Tilesets {
+		if ts.Contains(gid) {
+			return ts, gid - ts.FirstGID, true
+		}
+	}
+	return nil, 0, false
+}
+
+// TileProperties returns the custom properties of a tile, if any.
+func (m *Map) TileProperties(gid uint32) Properties {
+	ts, id, ok := m.Tileset(gid)
+	if !ok {
+		return nil
+	}
+	return ts.TileProperties[id]
+}
+
+// Bounds returns the size of the map in world coordinates.
+func (m *Map) Bounds() pixel.Rect {
+	return pixel.R(0, 0, float64(m.Width*m.TileWidth), float64(m.Height*m.TileHeight))
+}
+
+// TileAt returns the tile coordinates containing a point in world coordinates.
+func (m *Map) TileAt(v pixel.Vec) (x, y int) {
+	x = int(math.Floor(v.X / float64(m.TileWidth)))
+	y = m.Height - 1 - int(math.Floor(v.Y/float64(m.TileHeight)))
+	return x, y
+}
+
+// TileBounds returns the world bounds of the tile at (x, y).
+func (m *Map) TileBounds(x, y int) pixel.Rect {
+	minX := float64(x * m.TileWidth)
+	minY := float64((m.Height - 1 - y) * m.TileHeight)
+	return pixel.R(minX, minY, minX+float64(m.TileWidth), minY+float64(m.TileHeight))
+}
+
+// ObjectPosition converts an object's Tiled position into world coordinates.
+func (m *Map) ObjectPosition(o *Object) pixel.Vec {
+	return pixel.V(o.X, float64(m.Height*m.TileHeight)-o.Y)
+}
+
+// Collides reports whether rect overlaps any tile of layer for which solid
+// returns true. rect is in world coordinates.
+func (m *Map) Collides(layer *Layer, rect pixel.Rect, solid func(gid uint32) bool) bool {
+	minX, maxY := m.TileAt(rect.Min)
+	// the max edge is exclusive, so a rect flush against a tile boundary does
+	// not reach into the tile beyond it
+	maxX := int(math.Ceil(rect.Max.X/float64(m.TileWidth))) - 1
+	minY := m.Height - int(math.Ceil(rect.Max.Y/float64(m.TileHeight)))
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			gid := GID(layer.Tile(x, y))
+			if gid != 0 && solid(gid) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SolidProperty returns a solid func for Collides that checks a boolean tile
+// property, e.g. "solid" or "collides".
+func (m *Map) SolidProperty(name string) func(gid uint32) bool {
+	return func(gid uint32) bool {
+		return m.TileProperties(gid).Bool(name)
+	}
+}
+
+func (m *Map) validate() error {
+	if m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 {
+		return fmt.Errorf("invalid map size %dx%d with %dx%d tiles", m.Width, m.Height, m.TileWidth, m.TileHeight)
+	}
+	for _, layer := range m.Layers {
+		if len(layer.Tiles) != layer.Width*layer.Height {
+			return fmt.Errorf("layer %q has %d tiles, expected %d", layer.Name, len(layer.Tiles), layer.Width*layer.Height)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package tilemap
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func TestCollides(t *testing.T) {
+	// a 3x3 map of 16px tiles with a solid tile in the middle
+	m := &Map{Width: 3, Height: 3, TileWidth: 16, TileHeight: 16}
+	layer := &Layer{Width: 3, Height: 3, Tiles: []uint32{
+		0, 0, 0,
+		0, 1, 0,
+		0, 0, 0,
+	}}
+	solid := func(gid uint32) bool { return true }
+
+	tests := []struct {
+		name string
+		rect pixel.Rect
+		want bool
+	}{
+		{"inside", pixel.R(20, 20, 28, 28), true},
+		{"overlapping", pixel.R(10, 10, 17, 17), true},
+		{"flush left", pixel.R(0, 16, 16, 32), false},
+		{"flush below", pixel.R(16, 0, 32, 16), false},
+		{"flush right", pixel.R(32, 16, 48, 32), false},
+		{"flush above", pixel.R(16, 32, 32, 48), false},
+		{"corner", pixel.R(0, 0, 16, 16), false},
+		{"just over", pixel.R(0.5, 16, 16.5, 32), true},
+		{"covering", pixel.R(0, 0, 48, 48), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := m.Collides(layer, test.rect, solid); got != test.want {
+				t.Errorf("Collides(%v) = %v, want %v", test.rect, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package tilemap
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Renderer draws the visible part of a map's tile layers.
+type Renderer struct {
+	m        *Map
+	pictures map[*Tileset]pixel.Picture
+	batches  map[*Tileset]*pixel.Batch
+	sprites  map[uint32]*pixel.Sprite
+}
+
+// NewRenderer creates a renderer for m. picture is called once per tileset
+// with its image path to obtain the picture to draw tiles from, for example
+// assets.Manager.Picture.
+func NewRenderer(m *Map, picture func(image string) (pixel.Picture, error)) (*Renderer, error) {
+	r := &Renderer{
+		m:        m,
+		pictures: make(map[*Tileset]pixel.Picture),
+		batches:  make(map[*Tileset]*pixel.Batch),
+		sprites:  make(map[uint32]*pixel.Sprite),
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Image == "" {
+			return nil, fmt.Errorf("tileset %q has no image", ts.Name)
+		}
+		pic, err := picture(ts.Image)
+		if err != nil {
+			return nil, err
+		}
+		r.pictures[ts] = pic
+		r.batches[ts] = pixel.NewBatch(&pixel.TrianglesData{}, pic)
+	}
+	return r, nil
+}
+
+// Draw draws every visible tile layer into t. camera maps world coordinates
+// to the target and viewport is the area of the target being shown; only
+// tiles that fall inside the viewport are drawn.
+func (r *Renderer) Draw(t pixel.Target, camera pixel.Matrix, viewport pixel.Rect) {
+	for _, layer := range r.m.Layers {
+		if layer.Visible {
+			r.DrawLayer(t, layer, camera, viewport)
+		}
+	}
+}
+
+// DrawLayer draws a single tile layer, see Draw.
+func (r *Renderer) DrawLayer(t pixel.Target, layer *Layer, camera pixel.Matrix, viewport pixel.Rect) {
+	minX, minY, maxX, maxY := r.visibleTiles(camera, viewport)
+
+	for _, batch := range r.batches {
+		batch.Clear()
+	}
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			tile := layer.Tile(x, y)
+			if GID(tile) == 0 {
+				continue
+			}
+			ts, _, ok := r.m.Tileset(tile)
+			if !ok {
+				continue
+			}
+			r.sprite(ts, GID(tile)).Draw(r.batches[ts], r.tileMatrix(ts, tile, x, y))
+		}
+	}
+
+	mask := pixel.Alpha(layer.Opacity)
+	for _, ts := range r.m.Tilesets {
+		batch := r.batches[ts]
+		batch.SetMatrix(camera)
+		batch.SetColorMask(mask)
+		batch.Draw(t)
+	}
+}
+
+// visibleTiles returns the range of tile coordinates covered by viewport.
+func (r *Renderer) visibleTiles(camera pixel.Matrix, viewport pixel.Rect) (minX, minY, maxX, maxY int) {
+	corners := []pixel.Vec{
+		viewport.Min,
+		viewport.Max,
+		pixel.V(viewport.Min.X, viewport.Max.Y),
+		pixel.V(viewport.Max.X, viewport.Min.Y),
+	}
+	world := pixel.Rect{
+		Min: pixel.V(math.Inf(1), math.Inf(1)),
+		Max: pixel.V(math.Inf(-1), math.Inf(-1)),
+	}
+	for _, corner := range corners {
+		v := camera.Unproject(corner)
+		world.Min.X = math.Min(world.Min.X, v.X)
+		world.Min.Y = math.Min(world.Min.Y, v.Y)
+		world.Max.X = math.Max(world.Max.X, v.X)
+		world.Max.Y = math.Max(world.Max.Y, v.Y)
+	}
+
+	minX, maxY = r.m.TileAt(world.Min)
+	maxX, minY = r.m.TileAt(world.Max)
+	// tiles taller than the grid hang over the row above
+	minY -= r.overhang()
+
+	minX = int(math.Max(0, float64(minX)))
+	minY = int(math.Max(0, float64(minY)))
+	maxX = int(math.Min(float64(r.m.Width-1), float64(maxX)))
+	maxY = int(math.Min(float64(r.m.Height-1), float64(maxY)))
+	return minX, minY, maxX, maxY
+}
+
+func (r *Renderer) overhang() int {
+	rows := 0
+	for _, ts := range r.m.Tilesets {
+		extra := int(math.Ceil(float64(ts.TileHeight)/float64(r.m.TileHeight))) - 1
+		if extra > rows {
+			rows = extra
+		}
+	}
+	return rows
+}
+
+func (r *Renderer) sprite(ts *Tileset, gid uint32) *pixel.Sprite {
+	if sprite, ok := r.sprites[gid]; ok {
+		return sprite
+	}
+	pic := r.pictures[ts]
+	sprite := pixel.NewSprite(pic, ts.Frame(gid-ts.FirstGID, pic.Bounds().H()).Moved(pic.Bounds().Min))
+	r.sprites[gid] = sprite
+	return sprite
+}
+
+// tileMatrix places a tile in world coordinates. Tiles are anchored to the
+// bottom-left corner of their cell, as in Tiled.
+func (r *Renderer) tileMatrix(ts *Tileset, tile uint32, x, y int) pixel.Matrix {
+	cell := r.m.TileBounds(x, y)
+	center := cell.Min.Add(pixel.V(float64(ts.TileWidth)/2, float64(ts.TileHeight)/2))
+
+	matrix := pixel.IM
+	if tile&FlippedDiagonally != 0 {
+		// a diagonal flip transposes the image, which with y pointing up
+		// maps (x, y) to (-y, -x): a clockwise quarter turn then a mirror
+		matrix = matrix.Rotated(pixel.ZV, -math.Pi/2).ScaledXY(pixel.ZV, pixel.V(-1, 1))
+	}
+	if tile&FlippedHorizontally != 0 {
+		matrix = matrix.ScaledXY(pixel.ZV, pixel.V(-1, 1))
+	}
+	if tile&FlippedVertically != 0 {
+		matrix = matrix.ScaledXY(pixel.ZV, pixel.V(1, -1))
+	}
+	return matrix.Moved(center)
+}
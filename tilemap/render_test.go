@@ -0,0 +1,46 @@
+package tilemap
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func TestTileMatrixRotations(t *testing.T) {
+	m := &Map{Width: 1, Height: 1, TileWidth: 2, TileHeight: 2}
+	ts := &Tileset{TileWidth: 2, TileHeight: 2}
+	r := &Renderer{m: m}
+
+	// corners of the tile image around its center, as drawn unflipped
+	topLeft, topRight := pixel.V(-1, 1), pixel.V(1, 1)
+	bottomLeft, bottomRight := pixel.V(-1, -1), pixel.V(1, -1)
+	center := pixel.V(1, 1)
+
+	tests := []struct {
+		name string
+		// flags are how Tiled stores each rotation
+		flags uint32
+		// topLeft and topRight are where the image's top corners end up
+		topLeft, topRight pixel.Vec
+	}{
+		{"none", 0, topLeft, topRight},
+		{"90 clockwise", FlippedDiagonally | FlippedHorizontally, topRight, bottomRight},
+		{"180", FlippedHorizontally | FlippedVertically, bottomRight, bottomLeft},
+		{"270 clockwise", FlippedDiagonally | FlippedVertically, bottomLeft, topLeft},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix := r.tileMatrix(ts, 1|test.flags, 0, 0)
+			for _, corner := range []struct{ from, want pixel.Vec }{
+				{topLeft, test.topLeft},
+				{topRight, test.topRight},
+			} {
+				got := matrix.Project(corner.from).Sub(center)
+				if math.Abs(got.X-corner.want.X) > 1e-9 || math.Abs(got.Y-corner.want.Y) > 1e-9 {
+					t.Errorf("corner %v went to %v, want %v", corner.from, got, corner.want)
+				}
+			}
+		})
+	}
+}
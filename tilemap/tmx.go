@@ -0,0 +1,341 @@
+package tilemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/faiface/pixel"
+)
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type tmxProperties struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+type tmxTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      tmxImage      `xml:"image"`
+	Tiles      []tmxTile     `xml:"tile"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+type tmxDataTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxData struct {
+	Encoding    string        `xml:"encoding,attr"`
+	Compression string        `xml:"compression,attr"`
+	Text        string        `xml:",chardata"`
+	Tiles       []tmxDataTile `xml:"tile"`
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+// tmxLayer holds any of layer, objectgroup or group, as TMX interleaves them.
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	Data       tmxData       `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Layers     []tmxLayer    `xml:",any"`
+	Properties tmxProperties `xml:"properties"`
+}
+
+type tmxMap struct {
+	Width       int           `xml:"width,attr"`
+	Height      int           `xml:"height,attr"`
+	TileWidth   int           `xml:"tilewidth,attr"`
+	TileHeight  int           `xml:"tileheight,attr"`
+	Orientation string        `xml:"orientation,attr"`
+	Infinite    int           `xml:"infinite,attr"`
+	Tilesets    []tmxTileset  `xml:"tileset"`
+	Layers      []tmxLayer    `xml:",any"`
+	Properties  tmxProperties `xml:"properties"`
+}
+
+// ParseTMX reads a map saved in Tiled's TMX format. Maps that reference
+// external tilesets must be opened with Load.
+func ParseTMX(data []byte) (*Map, error) {
+	return parseTMX(data, "", noExternalFiles)
+}
+
+func parseTMX(data []byte, dir string, readFile fileReader) (*Map, error) {
+	var tm tmxMap
+	if err := xml.Unmarshal(data, &tm); err != nil {
+		return nil, fmt.Errorf("unable to parse map: %v", err)
+	}
+	if tm.Infinite != 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+	if tm.Orientation != "" && tm.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported orientation %q", tm.Orientation)
+	}
+
+	properties, err := tm.Properties.decode()
+	if err != nil {
+		return nil, err
+	}
+	m := &Map{
+		Width:      tm.Width,
+		Height:     tm.Height,
+		TileWidth:  tm.TileWidth,
+		TileHeight: tm.TileHeight,
+		Properties: properties,
+	}
+
+	for _, tt := range tm.Tilesets {
+		if tt.Source != "" {
+			ts, err := readFile.tileset(tt.Source)
+			if err != nil {
+				return nil, err
+			}
+			ts.FirstGID = tt.FirstGID
+			m.Tilesets = append(m.Tilesets, ts)
+			continue
+		}
+		ts, err := tt.tileset(dir)
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	if err := m.addTMXLayers(tm.Layers); err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseTSX reads an external tileset in Tiled's TSX format. dir is the
+// directory of the tileset file, used to resolve its image.
+func parseTSX(data []byte, dir string) (*Tileset, error) {
+	var tt tmxTileset
+	if err := xml.Unmarshal(data, &tt); err != nil {
+		return nil, fmt.Errorf("unable to parse tileset: %v", err)
+	}
+	return tt.tileset(dir)
+}
+
+func (tt *tmxTileset) tileset(dir string) (*Tileset, error) {
+	ts := &Tileset{
+		FirstGID:       tt.FirstGID,
+		Name:           tt.Name,
+		TileWidth:      tt.TileWidth,
+		TileHeight:     tt.TileHeight,
+		Spacing:        tt.Spacing,
+		Margin:         tt.Margin,
+		TileCount:      tt.TileCount,
+		Columns:        tt.Columns,
+		Image:          resolvePath(dir, tt.Image.Source),
+		TileProperties: make(map[uint32]Properties),
+	}
+	for _, tile := range tt.Tiles {
+		properties, err := tile.Properties.decode()
+		if err != nil {
+			return nil, err
+		}
+		if len(properties) > 0 {
+			ts.TileProperties[tile.ID] = properties
+		}
+	}
+	return ts, nil
+}
+
+func (m *Map) addTMXLayers(layers []tmxLayer) error {
+	for _, tl := range layers {
+		properties, err := tl.Properties.decode()
+		if err != nil {
+			return err
+		}
+		switch tl.XMLName.Local {
+		case "layer":
+			tiles, err := tl.Data.tiles()
+			if err != nil {
+				return fmt.Errorf("layer %q: %v", tl.Name, err)
+			}
+			opacity := 1.0
+			if tl.Opacity != nil {
+				opacity = *tl.Opacity
+			}
+			m.Layers = append(m.Layers, &Layer{
+				Name:       tl.Name,
+				Width:      tl.Width,
+				Height:     tl.Height,
+				Visible:    visible(tl.Visible),
+				Opacity:    opacity,
+				Properties: properties,
+				Tiles:      tiles,
+			})
+		case "objectgroup":
+			group := &ObjectGroup{
+				Name:       tl.Name,
+				Visible:    visible(tl.Visible),
+				Properties: properties,
+			}
+			for _, to := range tl.Objects {
+				object, err := to.object()
+				if err != nil {
+					return err
+				}
+				group.Objects = append(group.Objects, object)
+			}
+			m.ObjectGroups = append(m.ObjectGroups, group)
+		case "group":
+			if err := m.addTMXLayers(tl.Layers); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// visible reads TMX's visible attribute, which is omitted when true.
+func visible(attr *int) bool {
+	return attr == nil || *attr != 0
+}
+
+func (td *tmxData) tiles() ([]uint32, error) {
+	switch td.Encoding {
+	case "":
+		tiles := make([]uint32, len(td.Tiles))
+		for i, tile := range td.Tiles {
+			tiles[i] = tile.GID
+		}
+		return tiles, nil
+	case "csv":
+		return decodeCSV(td.Text)
+	case "base64":
+		return decodeBase64(td.Text, td.Compression)
+	default:
+		return nil, fmt.Errorf("unsupported layer encoding %q", td.Encoding)
+	}
+}
+
+func (to *tmxObject) object() (*Object, error) {
+	properties, err := to.Properties.decode()
+	if err != nil {
+		return nil, err
+	}
+	kind := to.Type
+	if kind == "" {
+		kind = to.Class
+	}
+	object := &Object{
+		ID:         to.ID,
+		Name:       to.Name,
+		Type:       kind,
+		X:          to.X,
+		Y:          to.Y,
+		Width:      to.Width,
+		Height:     to.Height,
+		Rotation:   to.Rotation,
+		GID:        to.GID,
+		Visible:    visible(to.Visible),
+		Ellipse:    to.Ellipse != nil,
+		Point:      to.Point != nil,
+		Properties: properties,
+	}
+	if to.Polygon != nil {
+		if object.Polygon, err = parsePoints(to.Polygon.Points); err != nil {
+			return nil, err
+		}
+	}
+	if to.Polyline != nil {
+		if object.Polyline, err = parsePoints(to.Polyline.Points); err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
+
+// parsePoints reads TMX's "x1,y1 x2,y2 ..." point lists.
+func parsePoints(s string) ([]pixel.Vec, error) {
+	var points []pixel.Vec
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		x, err := strconv.ParseFloat(xy[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", pair, err)
+		}
+		y, err := strconv.ParseFloat(xy[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", pair, err)
+		}
+		points = append(points, pixel.V(x, y))
+	}
+	return points, nil
+}
+
+func (tp *tmxProperties) decode() (Properties, error) {
+	properties := Properties{}
+	for _, property := range tp.Properties {
+		value := property.Value
+		if value == "" {
+			// multi-line strings are stored as the element's text
+			value = property.Text
+		}
+		parsed, err := parseProperty(property.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid property %q: %v", property.Name, err)
+		}
+		properties[property.Name] = parsed
+	}
+	return properties, nil
+}